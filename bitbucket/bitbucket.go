@@ -0,0 +1,222 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/conventionalpulls/internal/rest"
+)
+
+// Option configures requests to Bitbucket Cloud or Server
+type Option func(c *client)
+
+// CloudOption configures requests to Bitbucket Cloud. Every Option is a CloudOption.
+type CloudOption interface {
+	applyCloud(c *client)
+}
+
+// ServerOption configures requests to Bitbucket Server. Every Option is a ServerOption.
+type ServerOption interface {
+	applyServer(c *client)
+}
+
+func (o Option) applyCloud(c *client) {
+	o(c)
+}
+
+func (o Option) applyServer(c *client) {
+	o(c)
+}
+
+// WithToken authenticates requests with a bearer token such as a Bitbucket Cloud access token or a Bitbucket Server
+// HTTP access token
+func WithToken(token string) Option {
+	return func(c *client) {
+		c.Auth = func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+}
+
+// WithBasicAuth authenticates requests with a username and an app password or personal access token
+func WithBasicAuth(username, password string) Option {
+	return func(c *client) {
+		c.Auth = func(req *http.Request) {
+			req.SetBasicAuth(username, password)
+		}
+	}
+}
+
+// WithHTTPClient sets the http.Client used for requests. The default is http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+type client struct {
+	rest.Client
+}
+
+func newClient(baseURL string) *client {
+	c := &client{
+		Client: rest.Client{Service: "Bitbucket"},
+	}
+	c.SetBaseURL(baseURL)
+	return c
+}
+
+// get sends a GET request for the path p and decodes the JSON response into v
+func (c *client) get(ctx context.Context, p string, v interface{}) error {
+	_, err := c.Get(ctx, c.URL(p, nil), v)
+	return err
+}
+
+// pullRequest is a pull request with the Bitbucket metadata used by Mapping
+type pullRequest struct {
+	conventionalpulls.PullRequest
+
+	// Branch is the source branch's name without "refs/heads/"
+	Branch string
+
+	// Properties are the pull request's properties with scalar values formatted as strings
+	Properties map[string]string
+}
+
+// pullGetter gets pull requests from Bitbucket Cloud or Server
+type pullGetter interface {
+	getPull(ctx context.Context, id int) (*pullRequest, error)
+}
+
+// Mapping derives a VersionChange from Bitbucket pull request metadata. When more than one prefix or property matches,
+// the greatest change wins. All comparisons are case-insensitive.
+type Mapping struct {
+	// TitlePrefixes map a title prefix such as "[breaking]" or "feat:" to a change. Leading spaces in the title are
+	// ignored.
+	TitlePrefixes map[string]conventionalpulls.VersionChange
+
+	// BranchPrefixes map a source branch prefix such as "feature/" to a change
+	BranchPrefixes map[string]conventionalpulls.VersionChange
+
+	// Properties map a pull request property, written "name=value", to a change. Only Bitbucket Server returns
+	// properties with a pull request.
+	Properties map[string]conventionalpulls.VersionChange
+
+	// LabelNames are the labels returned for each change. A nil LabelNames is
+	// conventionalpulls.DefaultLabelNames.
+	LabelNames map[conventionalpulls.VersionChange]string
+}
+
+// DefaultMapping is the Mapping for Bitbucket's default branching model
+var DefaultMapping = &Mapping{
+	BranchPrefixes: map[string]conventionalpulls.VersionChange{
+		"feature/": conventionalpulls.VersionChangeMinor,
+		"bugfix/":  conventionalpulls.VersionChangePatch,
+		"hotfix/":  conventionalpulls.VersionChangePatch,
+	},
+}
+
+// VersionChange returns the greatest change for the title, branch and properties. Returns false when nothing
+// matches.
+func (m *Mapping) VersionChange(title, branch string, properties map[string]string) (conventionalpulls.VersionChange, bool) {
+	change := conventionalpulls.VersionChangeNone
+	found := false
+	check := func(prefixes map[string]conventionalpulls.VersionChange, s string) {
+		s = strings.ToLower(s)
+		for prefix, c := range prefixes {
+			if !strings.HasPrefix(s, strings.ToLower(prefix)) {
+				continue
+			}
+			found = true
+			if c > change {
+				change = c
+			}
+		}
+	}
+	check(m.TitlePrefixes, strings.TrimLeft(title, " "))
+	check(m.BranchPrefixes, branch)
+	for name, value := range properties {
+		for prop, c := range m.Properties {
+			if !strings.EqualFold(prop, name+"="+value) {
+				continue
+			}
+			found = true
+			if c > change {
+				change = c
+			}
+		}
+	}
+	return change, found
+}
+
+func (m *Mapping) label(pull *pullRequest) (string, bool, error) {
+	change, ok := m.VersionChange(pull.Title, pull.Branch, pull.Properties)
+	if !ok {
+		return "", false, nil
+	}
+	labelNames := m.LabelNames
+	if labelNames == nil {
+		labelNames = conventionalpulls.DefaultLabelNames
+	}
+	label, ok := labelNames[change]
+	if !ok {
+		return "", false, fmt.Errorf("no label name is configured for %s", change)
+	}
+	return label, true, nil
+}
+
+type prLabelFetcher struct {
+	pulls   pullGetter
+	mapping *Mapping
+	getCtx  func() context.Context
+}
+
+func newPRLabelFetcher(ctx context.Context, pulls pullGetter, mapping *Mapping) *prLabelFetcher {
+	if mapping == nil {
+		mapping = DefaultMapping
+	}
+	return &prLabelFetcher{
+		pulls:   pulls,
+		mapping: mapping,
+		getCtx: func() context.Context {
+			return ctx
+		},
+	}
+}
+
+func (f *prLabelFetcher) FetchPRLabels(id int) ([]string, error) {
+	return f.FetchPRLabelsContext(f.getCtx(), id)
+}
+
+func (f *prLabelFetcher) FetchPRLabelsContext(ctx context.Context, id int) ([]string, error) {
+	pull, err := f.pulls.getPull(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	label, ok, err := f.mapping.label(pull)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []string{}, nil
+	}
+	return []string{label}, nil
+}
+
+type prFetcher struct {
+	pulls pullGetter
+}
+
+func (f *prFetcher) FetchPR(ctx context.Context, id int) (*conventionalpulls.PullRequest, error) {
+	pull, err := f.pulls.getPull(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &pull.PullRequest, nil
+}
+
+// ResponseErr is an error indicating Bitbucket responded with a non-2xx status
+type ResponseErr = rest.ResponseErr
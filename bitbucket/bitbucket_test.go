@@ -0,0 +1,56 @@
+package bitbucket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/conventionalpulls/internal/rest/resttest"
+)
+
+// testServer returns a Bitbucket stand-in that responds to each path with its body. Requests must be authorized
+// with authHeader.
+func testServer(t *testing.T, authHeader string, bodies map[string]interface{}) *httptest.Server {
+	t.Helper()
+	routes := make(map[string]http.HandlerFunc, len(bodies))
+	for p, body := range bodies {
+		routes[p] = resttest.JSONHandler(body)
+	}
+	return resttest.NewServer(t, "Authorization", authHeader, routes)
+}
+
+func TestMapping_VersionChange(t *testing.T) {
+	mapping := &Mapping{
+		TitlePrefixes: map[string]conventionalpulls.VersionChange{
+			"[breaking]": conventionalpulls.VersionChangeMajor,
+			"feat:":      conventionalpulls.VersionChangeMinor,
+			"docs:":      conventionalpulls.VersionChangeNone,
+		},
+		BranchPrefixes: DefaultMapping.BranchPrefixes,
+		Properties: map[string]conventionalpulls.VersionChange{
+			"semver=major": conventionalpulls.VersionChangeMajor,
+		},
+	}
+	for _, td := range []struct {
+		title      string
+		branch     string
+		properties map[string]string
+		want       conventionalpulls.VersionChange
+		wantOK     bool
+	}{
+		{title: "Add widgets", branch: "feature/widgets", want: conventionalpulls.VersionChangeMinor, wantOK: true},
+		{title: "Fix widgets", branch: "Hotfix/widgets", want: conventionalpulls.VersionChangePatch, wantOK: true},
+		{title: " [Breaking] remove widgets", branch: "bugfix/widgets", want: conventionalpulls.VersionChangeMajor, wantOK: true},
+		{title: "feat: add widgets", branch: "main", want: conventionalpulls.VersionChangeMinor, wantOK: true},
+		{title: "docs: explain widgets", branch: "widgets", want: conventionalpulls.VersionChangeNone, wantOK: true},
+		{title: "Fix widgets", branch: "bugfix/widgets", properties: map[string]string{"semver": "Major"}, want: conventionalpulls.VersionChangeMajor, wantOK: true},
+		{title: "Fix widgets", branch: "widgets", properties: map[string]string{"semver": "minor"}, want: conventionalpulls.VersionChangeNone, wantOK: false},
+		{title: "Fix widgets", branch: "release/widgets", want: conventionalpulls.VersionChangeNone, wantOK: false},
+	} {
+		got, ok := mapping.VersionChange(td.title, td.branch, td.properties)
+		require.Equal(t, td.want, got, td.title)
+		require.Equal(t, td.wantOK, ok, td.title)
+	}
+}
@@ -0,0 +1,93 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+// CloudBaseURL is the base URL for Bitbucket Cloud's REST API
+const CloudBaseURL = "https://api.bitbucket.org/2.0"
+
+type cloudBaseURL string
+
+func (u cloudBaseURL) applyCloud(c *client) {
+	c.SetBaseURL(string(u))
+}
+
+// WithCloudBaseURL sets the base URL for Bitbucket Cloud's REST API. The default is CloudBaseURL.
+func WithCloudBaseURL(baseURL string) CloudOption {
+	return cloudBaseURL(baseURL)
+}
+
+type cloudPulls struct {
+	client    *client
+	workspace string
+	repo      string
+}
+
+func newCloudPulls(workspace, repo string, opts []CloudOption) *cloudPulls {
+	c := newClient(CloudBaseURL)
+	for _, opt := range opts {
+		opt.applyCloud(c)
+	}
+	return &cloudPulls{
+		client:    c,
+		workspace: workspace,
+		repo:      repo,
+	}
+}
+
+func (c *cloudPulls) getPull(ctx context.Context, id int) (*pullRequest, error) {
+	var pull struct {
+		ID          int    `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Author      struct {
+			Nickname string `json:"nickname"`
+		} `json:"author"`
+		Source struct {
+			Branch struct {
+				Name string `json:"name"`
+			} `json:"branch"`
+		} `json:"source"`
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+	p := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d", url.PathEscape(c.workspace), url.PathEscape(c.repo), id)
+	err := c.client.get(ctx, p, &pull)
+	if err != nil {
+		return nil, err
+	}
+	return &pullRequest{
+		PullRequest: conventionalpulls.PullRequest{
+			Number: pull.ID,
+			Title:  pull.Title,
+			Body:   pull.Description,
+			Author: pull.Author.Nickname,
+			URL:    pull.Links.HTML.Href,
+			Labels: []string{},
+		},
+		Branch: pull.Source.Branch.Name,
+	}, nil
+}
+
+// NewCloudPRLabelFetcher returns a PRLabelFetcher for a Bitbucket Cloud repository. Bitbucket has no pull request
+// labels, so the only label returned is mapping's label for the pull request's change. No label is returned when
+// nothing in mapping matches. A nil mapping is DefaultMapping. Pull requests are fetched from Bitbucket Cloud with ctx
+// unless FetchPRLabelsContext is given another context.
+func NewCloudPRLabelFetcher(ctx context.Context, workspace, repo string, mapping *Mapping, opt ...CloudOption) conventionalpulls.PRLabelFetcher {
+	return newPRLabelFetcher(ctx, newCloudPulls(workspace, repo, opt), mapping)
+}
+
+// NewCloudPRFetcher returns a PRFetcher for a Bitbucket Cloud repository. The pull requests have no labels.
+func NewCloudPRFetcher(workspace, repo string, opt ...CloudOption) conventionalpulls.PRFetcher {
+	return &prFetcher{
+		pulls: newCloudPulls(workspace, repo, opt),
+	}
+}
@@ -0,0 +1,124 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+)
+
+func cloudPull(id int, title, branch string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          id,
+		"title":       title,
+		"description": "about " + title,
+		"author":      map[string]string{"nickname": "octocat"},
+		"source":      map[string]interface{}{"branch": map[string]string{"name": branch}},
+		"links":       map[string]interface{}{"html": map[string]string{"href": "https://bitbucket.org/foo/bar/pull-requests/1"}},
+	}
+}
+
+func TestNewCloudPRLabelFetcher(t *testing.T) {
+	server := testServer(t, "Bearer secret", map[string]interface{}{
+		"/2.0/repositories/foo/bar/pullrequests/1": cloudPull(1, "Add widgets", "feature/widgets"),
+		"/2.0/repositories/foo/bar/pullrequests/2": cloudPull(2, "Fix widgets", "hotfix/widgets"),
+		"/2.0/repositories/foo/bar/pullrequests/3": cloudPull(3, "Update widgets", "widgets"),
+		"/2.0/repositories/foo/bar/pullrequests/4": cloudPull(4, "[major] Remove widgets", "widgets"),
+	})
+	opts := []CloudOption{
+		WithCloudBaseURL(server.URL + "/2.0/"),
+		WithToken("secret"),
+		WithHTTPClient(server.Client()),
+	}
+
+	t.Run("DefaultMapping", func(t *testing.T) {
+		fetcher := NewCloudPRLabelFetcher(context.Background(), "foo", "bar", nil, opts...)
+		got, err := fetcher.FetchPRLabels(1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Minor Change"}, got)
+		got, err = fetcher.FetchPRLabels(2)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Patch"}, got)
+		got, err = fetcher.FetchPRLabels(3)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("LabelNames", func(t *testing.T) {
+		mapping := &Mapping{
+			TitlePrefixes: map[string]conventionalpulls.VersionChange{"[major]": conventionalpulls.VersionChangeMajor},
+			LabelNames:    map[conventionalpulls.VersionChange]string{conventionalpulls.VersionChangeMajor: "semver:major"},
+		}
+		fetcher := NewCloudPRLabelFetcher(context.Background(), "foo", "bar", mapping, opts...)
+		got, err := fetcher.FetchPRLabels(4)
+		require.NoError(t, err)
+		require.Equal(t, []string{"semver:major"}, got)
+
+		mapping.TitlePrefixes["add"] = conventionalpulls.VersionChangeMinor
+		_, err = fetcher.FetchPRLabels(1)
+		require.EqualError(t, err, "no label name is configured for Minor")
+	})
+
+	t.Run("config", func(t *testing.T) {
+		cfg := &conventionalpulls.Config{
+			PRLabelFetcher: NewCloudPRLabelFetcher(context.Background(), "foo", "bar", nil, opts...),
+			RequireLabels:  true,
+		}
+		got, err := cfg.NextVersion("v1.2.3", 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, "v1.3.0", got)
+		_, err = cfg.NextVersion("v1.2.3", 1, 3)
+		require.Equal(t, &conventionalpulls.PRMissingLabelErr{IDs: []int{3}}, err)
+	})
+
+	t.Run("FetchPRLabelsContext", func(t *testing.T) {
+		canceledCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fetcher := NewCloudPRLabelFetcher(canceledCtx, "foo", "bar", nil, opts...)
+		ctxFetcher, ok := fetcher.(conventionalpulls.ContextPRLabelFetcher)
+		require.True(t, ok)
+		got, err := ctxFetcher.FetchPRLabelsContext(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Minor Change"}, got)
+		_, err = ctxFetcher.FetchPRLabelsContext(canceledCtx, 1)
+		require.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		fetcher := NewCloudPRLabelFetcher(context.Background(), "foo", "bar", nil, opts...)
+		got, err := fetcher.FetchPRLabels(5)
+		require.EqualError(t, err, "Bitbucket returned 404 Not Found")
+		require.Equal(t, &ResponseErr{
+			Service:    "Bitbucket",
+			StatusCode: http.StatusNotFound,
+			Body:       `{"message":"not found"}`,
+		}, err)
+		require.Nil(t, got)
+	})
+}
+
+func TestNewCloudPRFetcher(t *testing.T) {
+	server := testServer(t, "Basic dXNlcjphcHAtcGFzc3dvcmQ=", map[string]interface{}{
+		"/2.0/repositories/foo/bar/pullrequests/1": cloudPull(1, "feat: add widgets", "feature/widgets"),
+	})
+	fetcher := NewCloudPRFetcher("foo", "bar",
+		WithCloudBaseURL(server.URL+"/2.0"),
+		WithBasicAuth("user", "app-password"),
+		WithHTTPClient(server.Client()),
+	)
+	got, err := fetcher.FetchPR(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, &conventionalpulls.PullRequest{
+		Number: 1,
+		Title:  "feat: add widgets",
+		Body:   "about feat: add widgets",
+		Author: "octocat",
+		URL:    "https://bitbucket.org/foo/bar/pull-requests/1",
+		Labels: []string{},
+	}, got)
+
+	_, err = NewCloudPRFetcher("foo", "bar", WithCloudBaseURL(server.URL+"/2.0")).FetchPR(context.Background(), 1)
+	require.EqualError(t, err, "Bitbucket returned 401 Unauthorized")
+}
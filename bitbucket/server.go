@@ -0,0 +1,104 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+type serverPulls struct {
+	client  *client
+	project string
+	repo    string
+}
+
+func newServerPulls(baseURL, project, repo string, opts []ServerOption) *serverPulls {
+	c := newClient(baseURL)
+	for _, opt := range opts {
+		opt.applyServer(c)
+	}
+	return &serverPulls{
+		client:  c,
+		project: project,
+		repo:    repo,
+	}
+}
+
+func (s *serverPulls) getPull(ctx context.Context, id int) (*pullRequest, error) {
+	var pull struct {
+		ID          int    `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Author      struct {
+			User struct {
+				Name string `json:"name"`
+			} `json:"user"`
+		} `json:"author"`
+		FromRef struct {
+			ID string `json:"id"`
+		} `json:"fromRef"`
+		Links struct {
+			Self []struct {
+				Href string `json:"href"`
+			} `json:"self"`
+		} `json:"links"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	p := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d", url.PathEscape(s.project), url.PathEscape(s.repo), id)
+	err := s.client.get(ctx, p, &pull)
+	if err != nil {
+		return nil, err
+	}
+	var htmlURL string
+	if len(pull.Links.Self) > 0 {
+		htmlURL = pull.Links.Self[0].Href
+	}
+	return &pullRequest{
+		PullRequest: conventionalpulls.PullRequest{
+			Number: pull.ID,
+			Title:  pull.Title,
+			Body:   pull.Description,
+			Author: pull.Author.User.Name,
+			URL:    htmlURL,
+			Labels: []string{},
+		},
+		Branch:     strings.TrimPrefix(pull.FromRef.ID, "refs/heads/"),
+		Properties: scalarProperties(pull.Properties),
+	}, nil
+}
+
+// scalarProperties returns the properties with string, number or boolean values formatted as strings
+func scalarProperties(raw map[string]json.RawMessage) map[string]string {
+	props := make(map[string]string, len(raw))
+	for name, value := range raw {
+		var v interface{}
+		if json.Unmarshal(value, &v) != nil {
+			continue
+		}
+		switch v.(type) {
+		case string, float64, bool:
+			props[name] = fmt.Sprint(v)
+		}
+	}
+	return props
+}
+
+// NewServerPRLabelFetcher returns a PRLabelFetcher for a repository on Bitbucket Server or Data Center at baseURL such
+// as "https://bitbucket.example.com". Bitbucket has no pull request labels, so the only label returned is mapping's
+// label for the pull request's change. No label is returned when nothing in mapping matches. A nil mapping is
+// DefaultMapping. ctx is used when the fetcher is called without a context.
+func NewServerPRLabelFetcher(ctx context.Context, baseURL, project, repo string, mapping *Mapping, opt ...ServerOption) conventionalpulls.PRLabelFetcher {
+	return newPRLabelFetcher(ctx, newServerPulls(baseURL, project, repo, opt), mapping)
+}
+
+// NewServerPRFetcher returns a PRFetcher for a repository on Bitbucket Server or Data Center at baseURL. The pull
+// requests have no labels.
+func NewServerPRFetcher(baseURL, project, repo string, opt ...ServerOption) conventionalpulls.PRFetcher {
+	return &prFetcher{
+		pulls: newServerPulls(baseURL, project, repo, opt),
+	}
+}
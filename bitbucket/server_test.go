@@ -0,0 +1,92 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+)
+
+func serverPull(id int, title, branch string, properties map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          id,
+		"title":       title,
+		"description": "about " + title,
+		"author":      map[string]interface{}{"user": map[string]string{"name": "octocat"}},
+		"fromRef":     map[string]string{"id": "refs/heads/" + branch, "displayId": branch},
+		"links": map[string]interface{}{"self": []map[string]string{
+			{"href": "https://bitbucket.example.com/projects/FOO/repos/bar/pull-requests/1"},
+		}},
+		"properties": properties,
+	}
+}
+
+func TestNewServerPRLabelFetcher(t *testing.T) {
+	server := testServer(t, "Bearer secret", map[string]interface{}{
+		"/rest/api/1.0/projects/FOO/repos/bar/pull-requests/1": serverPull(1, "Add widgets", "feature/widgets", nil),
+		"/rest/api/1.0/projects/FOO/repos/bar/pull-requests/2": serverPull(2, "Remove widgets", "bugfix/widgets", map[string]interface{}{
+			"semver":            "major",
+			"openTaskCount":     0,
+			"mergeResult":       map[string]interface{}{"outcome": "CLEAN"},
+			"resolvedTaskCount": 2,
+		}),
+		"/rest/api/1.0/projects/FOO/repos/bar/pull-requests/3": serverPull(3, "Update widgets", "widgets", map[string]interface{}{
+			"openTaskCount": 0,
+		}),
+	})
+	mapping := &Mapping{
+		BranchPrefixes: DefaultMapping.BranchPrefixes,
+		Properties: map[string]conventionalpulls.VersionChange{
+			"semver=major": conventionalpulls.VersionChangeMajor,
+		},
+	}
+	fetcher := NewServerPRLabelFetcher(context.Background(), server.URL+"/", "FOO", "bar", mapping,
+		WithToken("secret"),
+		WithHTTPClient(server.Client()),
+	)
+	for id, want := range map[int][]string{
+		1: {"Minor Change"},
+		2: {"Breaking Change"},
+		3: {},
+	} {
+		got, err := fetcher.FetchPRLabels(id)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	_, err := fetcher.FetchPRLabels(4)
+	require.EqualError(t, err, "Bitbucket returned 404 Not Found")
+}
+
+func TestNewServerPRFetcher(t *testing.T) {
+	server := testServer(t, "Bearer secret", map[string]interface{}{
+		"/rest/api/1.0/projects/FOO/repos/bar/pull-requests/1": serverPull(1, "feat: add widgets", "feature/widgets", nil),
+	})
+	fetcher := NewServerPRFetcher(server.URL, "FOO", "bar", WithToken("secret"), WithHTTPClient(server.Client()))
+	got, err := fetcher.FetchPR(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, &conventionalpulls.PullRequest{
+		Number: 1,
+		Title:  "feat: add widgets",
+		Body:   "about feat: add widgets",
+		Author: "octocat",
+		URL:    "https://bitbucket.example.com/projects/FOO/repos/bar/pull-requests/1",
+		Labels: []string{},
+	}, got)
+}
+
+func Test_scalarProperties(t *testing.T) {
+	got := scalarProperties(map[string]json.RawMessage{
+		"string": json.RawMessage(`"major"`),
+		"number": json.RawMessage(`2`),
+		"bool":   json.RawMessage(`true`),
+		"object": json.RawMessage(`{"outcome":"CLEAN"}`),
+		"null":   json.RawMessage(`null`),
+	})
+	require.Equal(t, map[string]string{
+		"string": "major",
+		"number": "2",
+		"bool":   "true",
+	}, got)
+}
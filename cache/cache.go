@@ -0,0 +1,105 @@
+// Package cache caches pull request labels and GitHub responses on disk so repeated runs don't refetch them.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Store keeps cached values as JSON files in a directory. It is safe for concurrent use by multiple goroutines and
+// processes.
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// NewStore returns a Store that keeps its files in dir. dir is created when the first value is stored.
+func NewStore(dir string) *Store {
+	return &Store{
+		dir: dir,
+		now: time.Now,
+	}
+}
+
+// DefaultDir returns the "conventionalpulls" directory in the user's cache directory
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "conventionalpulls"), nil
+}
+
+type entry struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// filename returns the file for key. Keys are hashed so they can contain any characters.
+func (s *Store) filename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(s.dir, name[:2], name+".json")
+}
+
+// get decodes the value for key into v. Returns false when there is no value for key or it can't be read.
+func (s *Store) get(key string, v interface{}) (storedAt time.Time, ok bool) {
+	data, err := ioutil.ReadFile(s.filename(key))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var e entry
+	err = json.Unmarshal(data, &e)
+	if err != nil || e.Key != key {
+		return time.Time{}, false
+	}
+	err = json.Unmarshal(e.Value, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return e.StoredAt, true
+}
+
+// put stores v as the value for key. The file is written to a temp file and renamed so readers never see a partial
+// value.
+func (s *Store) put(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(&entry{
+		Key:      key,
+		StoredAt: s.now(),
+		Value:    value,
+	})
+	if err != nil {
+		return err
+	}
+	filename := s.filename(key)
+	err = os.MkdirAll(filepath.Dir(filename), 0o700)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
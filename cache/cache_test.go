@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testStore returns a Store in a temp dir whose clock is *now
+func testStore(t *testing.T, now *time.Time) *Store {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "conventionalpulls")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+	store := NewStore(filepath.Join(dir, "cache"))
+	store.now = func() time.Time {
+		return *now
+	}
+	return store
+}
+
+func TestStore(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := testStore(t, &now)
+
+	var got []string
+	_, ok := store.get("foo", &got)
+	require.False(t, ok)
+
+	err := store.put("foo", []string{"a", "b"})
+	require.NoError(t, err)
+	storedAt, ok := store.get("foo", &got)
+	require.True(t, ok)
+	require.Equal(t, []string{"a", "b"}, got)
+	require.True(t, now.Equal(storedAt))
+
+	now = now.Add(time.Hour)
+	err = store.put("foo", []string{"c"})
+	require.NoError(t, err)
+	storedAt, ok = store.get("foo", &got)
+	require.True(t, ok)
+	require.Equal(t, []string{"c"}, got)
+	require.True(t, now.Equal(storedAt))
+
+	t.Run("corrupt file", func(t *testing.T) {
+		err := store.put("bar", []string{"a"})
+		require.NoError(t, err)
+		err = ioutil.WriteFile(store.filename("bar"), []byte("{"), 0o600)
+		require.NoError(t, err)
+		_, ok := store.get("bar", &got)
+		require.False(t, ok)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		var n int
+		_, ok := store.get("foo", &n)
+		require.False(t, ok)
+	})
+
+	t.Run("unwritable", func(t *testing.T) {
+		err := ioutil.WriteFile(filepath.Join(store.dir, "file"), nil, 0o600)
+		require.NoError(t, err)
+		unwritable := NewStore(filepath.Join(store.dir, "file"))
+		err = unwritable.put("foo", "bar")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+type prLabelFetcher struct {
+	fetcher conventionalpulls.ContextPRLabelFetcher
+	store   *Store
+	repoKey string
+	ttl     time.Duration
+}
+
+// NewPRLabelFetcher returns a PRLabelFetcher that keeps fetcher's labels in store for ttl. repoKey identifies the
+// repository in the cache as host/owner/repo, such as "github.com/willabides/conventionalpulls".
+//
+// Labels older than ttl are fetched again. When fetcher makes its requests through a client using
+// Store.Transport, those fetches are conditional requests that GitHub answers with 304 Not Modified when nothing
+// changed. Failing to write to store isn't an error because the labels were still fetched.
+//
+// The returned fetcher also implements conventionalpulls.ContextPRLabelFetcher.
+func NewPRLabelFetcher(fetcher conventionalpulls.PRLabelFetcher, store *Store, repoKey string, ttl time.Duration) conventionalpulls.PRLabelFetcher {
+	return &prLabelFetcher{
+		fetcher: conventionalpulls.ContextFetcher(fetcher),
+		store:   store,
+		repoKey: repoKey,
+		ttl:     ttl,
+	}
+}
+
+func (f *prLabelFetcher) FetchPRLabels(id int) ([]string, error) {
+	return f.FetchPRLabelsContext(context.Background(), id)
+}
+
+func (f *prLabelFetcher) FetchPRLabelsContext(ctx context.Context, id int) ([]string, error) {
+	key := fmt.Sprintf("labels %s/%d", f.repoKey, id)
+	var labels []string
+	storedAt, ok := f.store.get(key, &labels)
+	if ok && f.store.now().Sub(storedAt) < f.ttl {
+		return labels, nil
+	}
+	labels, err := f.fetcher.FetchPRLabelsContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_ = f.store.put(key, labels)
+	return labels, nil
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/conventionalpulls/internal/mocks"
+)
+
+func TestNewPRLabelFetcher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := testStore(t, &now)
+	mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+	fetcher := NewPRLabelFetcher(mockFetcher, store, "github.com/foo/bar", time.Hour)
+	otherRepo := NewPRLabelFetcher(mockFetcher, store, "github.com/foo/baz", time.Hour)
+
+	mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"Patch"}, nil)
+	got, err := fetcher.FetchPRLabels(1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Patch"}, got)
+
+	// cached
+	now = now.Add(59 * time.Minute)
+	got, err = fetcher.FetchPRLabels(1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Patch"}, got)
+
+	// other repositories have their own labels
+	mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{}, nil)
+	got, err = otherRepo.FetchPRLabels(1)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	// expired
+	now = now.Add(time.Minute)
+	mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"Minor Change"}, nil)
+	got, err = fetcher.FetchPRLabels(1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Minor Change"}, got)
+
+	// errors aren't cached
+	mockFetcher.EXPECT().FetchPRLabels(2).Return(nil, assert.AnError)
+	_, err = fetcher.FetchPRLabels(2)
+	require.Equal(t, assert.AnError, err)
+	mockFetcher.EXPECT().FetchPRLabels(2).Return([]string{"Patch"}, nil)
+	got, err = fetcher.FetchPRLabels(2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Patch"}, got)
+
+	t.Run("Config", func(t *testing.T) {
+		cfg := &conventionalpulls.Config{PRLabelFetcher: fetcher}
+		got, err := cfg.NextVersion("v1.0.0", 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, "v1.1.0", got)
+	})
+
+	t.Run("FetchPRLabelsContext", func(t *testing.T) {
+		ctxFetcher, ok := fetcher.(conventionalpulls.ContextPRLabelFetcher)
+		require.True(t, ok)
+		canceledCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := ctxFetcher.FetchPRLabelsContext(canceledCtx, 3)
+		require.Error(t, err)
+	})
+}
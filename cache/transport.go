@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+type storedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+type transport struct {
+	store *Store
+	base  http.RoundTripper
+}
+
+// Transport returns an http.RoundTripper that uses conditional requests to revalidate responses kept in s. Successful
+// GET responses with an ETag header are stored. Later requests for the same URL send If-None-Match, and a 304 Not
+// Modified response is replaced with the stored response. A nil base is http.DefaultTransport.
+//
+// Responses are stored separately for each Authorization header so a token never sees another token's responses.
+func (s *Store) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{
+		store: s,
+		base:  base,
+	}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return t.base.RoundTrip(req)
+	}
+	key := responseKey(req)
+	var stored storedResponse
+	_, ok := t.store.get(key, &stored)
+	etag := stored.Header.Get("ETag")
+	if ok && etag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok && etag != "" && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		return stored.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == "" {
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	_ = t.store.put(key, &storedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	})
+	return resp, nil
+}
+
+func (r *storedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// responseKey returns the store key for req's response. The Authorization header is hashed so tokens aren't written
+// to disk.
+func responseKey(req *http.Request) string {
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return "response " + req.URL.String() + " " + req.Header.Get("Accept") + " " + hex.EncodeToString(auth[:])
+}
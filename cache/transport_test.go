@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls/github"
+	"github.com/willabides/octo-go"
+)
+
+// etagServer serves bodies by path with ETags and answers matching If-None-Match headers with 304
+type etagServer struct {
+	bodies   map[string]string
+	requests []string
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.bodies[r.URL.Path]
+	if !ok {
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		s.requests = append(s.requests, "404")
+		return
+	}
+	etag := fmt.Sprintf(`"%x"`, len(body))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		s.requests = append(s.requests, "304")
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(body))
+	s.requests = append(s.requests, "200")
+}
+
+func testGet(t *testing.T, client *http.Client, u, auth string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", auth)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp.StatusCode, string(body)
+}
+
+func TestStore_Transport(t *testing.T) {
+	now := time.Now()
+	handler := &etagServer{bodies: map[string]string{"/foo": "foo"}}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client := &http.Client{Transport: testStore(t, &now).Transport(nil)}
+
+	status, body := testGet(t, client, server.URL+"/foo", "token a")
+	require.Equal(t, 200, status)
+	require.Equal(t, "foo", body)
+
+	status, body = testGet(t, client, server.URL+"/foo", "token a")
+	require.Equal(t, 200, status)
+	require.Equal(t, "foo", body)
+
+	// changed
+	handler.bodies["/foo"] = "foo v2"
+	status, body = testGet(t, client, server.URL+"/foo", "token a")
+	require.Equal(t, 200, status)
+	require.Equal(t, "foo v2", body)
+
+	// another token
+	status, body = testGet(t, client, server.URL+"/foo", "token b")
+	require.Equal(t, 200, status)
+	require.Equal(t, "foo v2", body)
+
+	status, _ = testGet(t, client, server.URL+"/bar", "token a")
+	require.Equal(t, 404, status)
+
+	resp, err := client.Post(server.URL+"/foo", "text/plain", strings.NewReader("foo"))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, []string{"200", "304", "200", "200", "404", "200"}, handler.requests)
+}
+
+func TestStore_Transport_github(t *testing.T) {
+	now := time.Now()
+	handler := &etagServer{bodies: map[string]string{
+		"/repos/foo/bar/pulls/1": `{"number": 1, "labels": [{"name": "Patch"}]}`,
+	}}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	baseURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	store := testStore(t, &now)
+	fetcher := NewPRLabelFetcher(
+		github.NewPRLabelFetcher(context.Background(), "foo", "bar",
+			octo.WithBaseURL(*baseURL),
+			octo.WithHTTPClient(&http.Client{Transport: store.Transport(nil)}),
+		),
+		store, "github.com/foo/bar", time.Minute,
+	)
+	for i := 0; i < 3; i++ {
+		got, err := fetcher.FetchPRLabels(1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Patch"}, got)
+		now = now.Add(time.Minute)
+	}
+	require.Equal(t, []string{"200", "304", "304"}, handler.requests)
+}
@@ -0,0 +1,111 @@
+// Package changelog renders release notes from labeled pull requests.
+package changelog
+
+import (
+	"context"
+	"io"
+	"text/template"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+const defaultTemplate = `## {{ .Version }}
+{{ range .Sections }}
+### {{ .Title }}
+
+{{ range .PullRequests -}}
+{{ template "entry" . }}
+{{ end -}}
+{{ end -}}
+
+{{- define "entry" -}}
+- {{ .Title }} ({{ if .URL }}[#{{ .Number }}]({{ .URL }}){{ else }}#{{ .Number }}{{ end }}){{ if .Author }} @{{ .Author }}{{ end }}
+{{- end -}}
+`
+
+// DefaultTemplate is the Markdown template used when Generator.Template is nil. It is executed with a *Data.
+// Its "entry" template renders a single *conventionalpulls.PullRequest as a list item.
+var DefaultTemplate = template.Must(template.New("changelog").Parse(defaultTemplate))
+
+var sectionTitles = map[conventionalpulls.VersionChange]string{
+	conventionalpulls.VersionChangeMajor: "Breaking Changes",
+	conventionalpulls.VersionChangeMinor: "Features",
+	conventionalpulls.VersionChangePatch: "Fixes",
+	conventionalpulls.VersionChangeNone:  "Other",
+}
+
+// Section is a group of pull requests with the same VersionChange
+type Section struct {
+	Title         string
+	VersionChange conventionalpulls.VersionChange
+	PullRequests  []*conventionalpulls.PullRequest
+}
+
+// Data is the data passed to a changelog template
+type Data struct {
+	Version  string
+	Sections []Section
+}
+
+// Sections groups pulls by the VersionChange cfg assigns to their labels, with the greatest change first. Pull
+// requests keep their order within a section, and sections without pull requests are omitted.
+func Sections(cfg *conventionalpulls.Config, pulls []*conventionalpulls.PullRequest) []Section {
+	grouped := map[conventionalpulls.VersionChange][]*conventionalpulls.PullRequest{}
+	for _, pull := range pulls {
+		change := cfg.LabelsVersionChange(pull.Labels)
+		grouped[change] = append(grouped[change], pull)
+	}
+	var sections []Section
+	for change := conventionalpulls.VersionChangeMajor; change >= conventionalpulls.VersionChangeNone; change-- {
+		if len(grouped[change]) == 0 {
+			continue
+		}
+		sections = append(sections, Section{
+			Title:         sectionTitles[change],
+			VersionChange: change,
+			PullRequests:  grouped[change],
+		})
+	}
+	return sections
+}
+
+// Generator generates changelogs
+type Generator struct {
+	Config    *conventionalpulls.Config
+	PRFetcher conventionalpulls.PRFetcher
+
+	// Template is executed with a *Data to render the changelog. Default is DefaultTemplate.
+	Template *template.Template
+}
+
+// Generate fetches the given pull requests and writes the changelog for version to w
+func (g *Generator) Generate(ctx context.Context, w io.Writer, version string, pullRequestID ...int) error {
+	if g.PRFetcher == nil {
+		panic("PRFetcher shant be nil")
+	}
+	pulls := make([]*conventionalpulls.PullRequest, len(pullRequestID))
+	for i, id := range pullRequestID {
+		pull, err := g.PRFetcher.FetchPR(ctx, id)
+		if err != nil {
+			return err
+		}
+		pulls[i] = pull
+	}
+	return g.Render(w, version, pulls)
+}
+
+// Render writes the changelog for version with the given pull requests to w
+func (g *Generator) Render(w io.Writer, version string, pulls []*conventionalpulls.PullRequest) error {
+	tmpl := g.Template
+	if tmpl == nil {
+		tmpl = DefaultTemplate
+	}
+	cfg := g.Config
+	if cfg == nil {
+		cfg = new(conventionalpulls.Config)
+	}
+	return tmpl.Execute(w, &Data{
+		Version:  version,
+		Sections: Sections(cfg, pulls),
+	})
+}
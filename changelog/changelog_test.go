@@ -0,0 +1,154 @@
+package changelog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+)
+
+type fakePRFetcher map[int]*conventionalpulls.PullRequest
+
+func (f fakePRFetcher) FetchPR(_ context.Context, id int) (*conventionalpulls.PullRequest, error) {
+	pull, ok := f[id]
+	if !ok {
+		return nil, assert.AnError
+	}
+	return pull, nil
+}
+
+var testPulls = fakePRFetcher{
+	1: {Number: 1, Title: "fix the thing", Author: "alice", URL: "https://github.com/foo/bar/pull/1", Labels: []string{"Patch"}},
+	2: {Number: 2, Title: "add a thing", Author: "bob", URL: "https://github.com/foo/bar/pull/2", Labels: []string{"minor change"}},
+	3: {Number: 3, Title: "remove the old thing", Author: "carol", URL: "https://github.com/foo/bar/pull/3", Labels: []string{"Breaking Change", "Patch"}},
+	4: {Number: 4, Title: "update ci", Labels: []string{"Non-Production Change"}},
+	5: {Number: 5, Title: "fix another thing", Author: "alice", URL: "https://github.com/foo/bar/pull/5", Labels: []string{"patch"}},
+	6: {Number: 6, Title: "unlabeled", Author: "dave", URL: "https://github.com/foo/bar/pull/6"},
+}
+
+func TestSections(t *testing.T) {
+	pulls := []*conventionalpulls.PullRequest{testPulls[1], testPulls[2], testPulls[4], testPulls[5]}
+	got := Sections(new(conventionalpulls.Config), pulls)
+	require.Equal(t, []Section{
+		{
+			Title:         "Features",
+			VersionChange: conventionalpulls.VersionChangeMinor,
+			PullRequests:  []*conventionalpulls.PullRequest{testPulls[2]},
+		},
+		{
+			Title:         "Fixes",
+			VersionChange: conventionalpulls.VersionChangePatch,
+			PullRequests:  []*conventionalpulls.PullRequest{testPulls[1], testPulls[5]},
+		},
+		{
+			Title:         "Other",
+			VersionChange: conventionalpulls.VersionChangeNone,
+			PullRequests:  []*conventionalpulls.PullRequest{testPulls[4]},
+		},
+	}, got)
+
+	t.Run("custom labels", func(t *testing.T) {
+		cfg := &conventionalpulls.Config{
+			LabelValues: map[string]conventionalpulls.VersionChange{
+				"semver:major": conventionalpulls.VersionChangeMajor,
+			},
+		}
+		pull := &conventionalpulls.PullRequest{Number: 7, Labels: []string{"semver:major"}}
+		got := Sections(cfg, []*conventionalpulls.PullRequest{pull, testPulls[1]})
+		require.Equal(t, []Section{
+			{
+				Title:         "Breaking Changes",
+				VersionChange: conventionalpulls.VersionChangeMajor,
+				PullRequests:  []*conventionalpulls.PullRequest{pull},
+			},
+			{
+				Title:         "Other",
+				VersionChange: conventionalpulls.VersionChangeNone,
+				PullRequests:  []*conventionalpulls.PullRequest{testPulls[1]},
+			},
+		}, got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Empty(t, Sections(new(conventionalpulls.Config), nil))
+	})
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	t.Run("default template", func(t *testing.T) {
+		generator := &Generator{
+			PRFetcher: testPulls,
+		}
+		var buf bytes.Buffer
+		err := generator.Generate(context.Background(), &buf, "v2.0.0", 1, 2, 3, 4, 5, 6)
+		require.NoError(t, err)
+		want := `## v2.0.0
+
+### Breaking Changes
+
+- remove the old thing ([#3](https://github.com/foo/bar/pull/3)) @carol
+
+### Features
+
+- add a thing ([#2](https://github.com/foo/bar/pull/2)) @bob
+
+### Fixes
+
+- fix the thing ([#1](https://github.com/foo/bar/pull/1)) @alice
+- fix another thing ([#5](https://github.com/foo/bar/pull/5)) @alice
+
+### Other
+
+- update ci (#4)
+- unlabeled ([#6](https://github.com/foo/bar/pull/6)) @dave
+`
+		require.Equal(t, want, buf.String())
+	})
+
+	t.Run("no pulls", func(t *testing.T) {
+		generator := &Generator{
+			PRFetcher: testPulls,
+		}
+		var buf bytes.Buffer
+		err := generator.Generate(context.Background(), &buf, "v1.0.0")
+		require.NoError(t, err)
+		require.Equal(t, "## v1.0.0\n", buf.String())
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		tmpl := template.Must(template.New("").Funcs(template.FuncMap{
+			"sprintf": fmt.Sprintf,
+		}).Parse(`{{ .Version }}:{{ range .Sections }}{{ range .PullRequests }} {{ sprintf "%s/%d" $.Version .Number }}{{ end }}{{ end }}`))
+		generator := &Generator{
+			PRFetcher: testPulls,
+			Template:  tmpl,
+		}
+		var buf bytes.Buffer
+		err := generator.Generate(context.Background(), &buf, "v1.3.0", 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, "v1.3.0: v1.3.0/2 v1.3.0/1", buf.String())
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		generator := &Generator{
+			PRFetcher: testPulls,
+		}
+		var buf bytes.Buffer
+		err := generator.Generate(context.Background(), &buf, "v1.3.0", 1, 99)
+		require.Equal(t, assert.AnError, err)
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("nil PRFetcher", func(t *testing.T) {
+		require.Panics(t, func() {
+			var buf bytes.Buffer
+			err := new(Generator).Generate(context.Background(), &buf, "v1.3.0", 1)
+			require.NoError(t, err)
+		})
+	})
+}
@@ -0,0 +1,275 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	releaseHeadingExp = regexp.MustCompile(`^## \[([^\]]+)\]`)
+	unreleasedLinkExp = regexp.MustCompile(`^\[Unreleased\]:\s*(\S+)/compare/(\S+)\.\.\.(\S+)\s*$`)
+	releaseLinkExp    = regexp.MustCompile(`^\[([^\]]+)\]:\s*(\S+?)/(?:compare/\S+\.\.\.|releases/tag/)(\S+)\s*$`)
+	linkDefinitionExp = regexp.MustCompile(`^\[[^\]]+\]:\s*\S+`)
+)
+
+// Release is a release to add to a changelog in the keepachangelog.com format
+type Release struct {
+	Version string
+	Date    time.Time
+
+	// Tag is the git tag for Version that is used in compare links. Default is Version.
+	Tag string
+
+	Sections []Section
+}
+
+func (r *Release) tag() string {
+	if r.Tag == "" {
+		return r.Version
+	}
+	return r.Tag
+}
+
+// UpdateKeepAChangelog returns the content of a keepachangelog.com formatted changelog with a section added for
+// release. Entries under the "[Unreleased]" heading are moved to the new section, and the "[Unreleased]" compare link
+// is updated to start at the new tag with a compare link for the release added after it. Without an "[Unreleased]"
+// link, the release's compare link is derived from the previous release's link and added before it. The changelog is
+// returned unchanged when it already has a section for release.Version.
+func UpdateKeepAChangelog(content []byte, release *Release) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+	for _, line := range lines {
+		match := releaseHeadingExp.FindStringSubmatch(line)
+		if match != nil && match[1] == release.Version {
+			return content, nil
+		}
+	}
+
+	unreleasedStart, insertAt := findUnreleased(lines)
+	var unreleased []string
+	if unreleasedStart >= 0 {
+		unreleased = lines[unreleasedStart+1 : insertAt]
+	} else {
+		insertAt = firstReleaseHeading(lines)
+	}
+
+	section, err := releaseSection(release, unreleased)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	if unreleasedStart >= 0 {
+		result = append(result, lines[:unreleasedStart+1]...)
+		result = append(result, "")
+	} else {
+		result = append(result, lines[:insertAt]...)
+		if insertAt > 0 && strings.TrimSpace(lines[insertAt-1]) != "" {
+			result = append(result, "")
+		}
+	}
+	result = append(result, section...)
+	result = append(result, "")
+	if !allBlank(lines[insertAt:]) {
+		result = append(result, lines[insertAt:]...)
+	}
+	result = updateLinks(result, release)
+	return []byte(strings.Join(result, "\n")), nil
+}
+
+// findUnreleased returns the index of the "[Unreleased]" heading and the index of the line that ends its section.
+// Returns -1 for start when there is no "[Unreleased]" heading.
+func findUnreleased(lines []string) (start, end int) {
+	start = -1
+	for i, line := range lines {
+		match := releaseHeadingExp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if start >= 0 {
+			return start, i
+		}
+		if strings.EqualFold(match[1], "Unreleased") {
+			start = i
+		}
+	}
+	if start < 0 {
+		return -1, -1
+	}
+	// the section ends at the link definitions at the bottom of the file
+	end = len(lines)
+	for end > start+1 && (strings.TrimSpace(lines[end-1]) == "" || linkDefinitionExp.MatchString(lines[end-1])) {
+		end--
+	}
+	for end < len(lines) && strings.TrimSpace(lines[end]) == "" {
+		end++
+	}
+	return start, end
+}
+
+// firstReleaseHeading returns the index of the first release heading. When there are no releases, it returns the
+// index following the last line of content.
+func firstReleaseHeading(lines []string) int {
+	for i, line := range lines {
+		if releaseHeadingExp.MatchString(line) {
+			return i
+		}
+	}
+	for i := len(lines); i > 0; i-- {
+		if strings.TrimSpace(lines[i-1]) != "" && !linkDefinitionExp.MatchString(lines[i-1]) {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+func allBlank(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// subsection is a "###" heading with its lines
+type subsection struct {
+	heading string
+	lines   []string
+}
+
+// parseSubsections splits lines into the lines before the first "###" heading and subsections
+func parseSubsections(lines []string) (preamble []string, subsections []*subsection) {
+	var current *subsection
+	for _, line := range lines {
+		if strings.HasPrefix(line, "### ") {
+			current = &subsection{heading: strings.TrimSpace(strings.TrimPrefix(line, "### "))}
+			subsections = append(subsections, current)
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if current == nil {
+			preamble = append(preamble, line)
+			continue
+		}
+		current.lines = append(current.lines, line)
+	}
+	return preamble, subsections
+}
+
+// releaseSection renders the lines for release, merging in the entries from unreleased
+func releaseSection(release *Release, unreleased []string) ([]string, error) {
+	preamble, subsections := parseSubsections(unreleased)
+	byHeading := make(map[string]*subsection, len(subsections))
+	for _, sub := range subsections {
+		byHeading[strings.ToLower(sub.heading)] = sub
+	}
+	var merged []*subsection
+	for _, section := range release.Sections {
+		sub := byHeading[strings.ToLower(section.Title)]
+		if sub == nil {
+			sub = &subsection{heading: section.Title}
+			subsections = append(subsections, sub)
+			byHeading[strings.ToLower(section.Title)] = sub
+		}
+		for _, pull := range section.PullRequests {
+			var buf bytes.Buffer
+			err := DefaultTemplate.ExecuteTemplate(&buf, "entry", pull)
+			if err != nil {
+				return nil, err
+			}
+			sub.lines = append(sub.lines, buf.String())
+		}
+		merged = append(merged, sub)
+	}
+	// generated sections come first, followed by any others from unreleased
+	for _, sub := range subsections {
+		if !containsSubsection(merged, sub) {
+			merged = append(merged, sub)
+		}
+	}
+
+	heading := fmt.Sprintf("## [%s]", release.Version)
+	if !release.Date.IsZero() {
+		heading += " - " + release.Date.Format("2006-01-02")
+	}
+	result := []string{heading}
+	if len(preamble) > 0 {
+		result = append(result, "")
+		result = append(result, preamble...)
+	}
+	for _, sub := range merged {
+		result = append(result, "", "### "+sub.heading, "")
+		result = append(result, sub.lines...)
+	}
+	return result, nil
+}
+
+func containsSubsection(subsections []*subsection, sub *subsection) bool {
+	for _, s := range subsections {
+		if s == sub {
+			return true
+		}
+	}
+	return false
+}
+
+// updateLinks points the "[Unreleased]" compare link at the new tag and adds a compare link for release
+func updateLinks(lines []string, release *Release) []string {
+	for i, line := range lines {
+		match := unreleasedLinkExp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		baseURL, prevTag, head := match[1], match[2], match[3]
+		result := make([]string, 0, len(lines)+1)
+		result = append(result, lines[:i]...)
+		result = append(result,
+			fmt.Sprintf("[Unreleased]: %s/compare/%s...%s", baseURL, release.tag(), head),
+			fmt.Sprintf("[%s]: %s/compare/%s...%s", release.Version, baseURL, prevTag, release.tag()),
+		)
+		return append(result, lines[i+1:]...)
+	}
+	return addReleaseLink(lines, release)
+}
+
+// addReleaseLink adds a compare link for release before the link for the release that precedes it. The previous tag
+// is the end of that link's compare range or the tag it links to.
+func addReleaseLink(lines []string, release *Release) []string {
+	prevVersion := previousRelease(lines, release.Version)
+	if prevVersion == "" {
+		return lines
+	}
+	for i, line := range lines {
+		match := releaseLinkExp.FindStringSubmatch(line)
+		if match == nil || match[1] != prevVersion {
+			continue
+		}
+		baseURL, prevTag := match[2], match[3]
+		result := make([]string, 0, len(lines)+1)
+		result = append(result, lines[:i]...)
+		result = append(result, fmt.Sprintf("[%s]: %s/compare/%s...%s", release.Version, baseURL, prevTag, release.tag()))
+		return append(result, lines[i:]...)
+	}
+	return lines
+}
+
+// previousRelease returns the version of the release heading that follows version's heading, or "" if there is none
+func previousRelease(lines []string, version string) string {
+	found := false
+	for _, line := range lines {
+		match := releaseHeadingExp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if found {
+			return match[1]
+		}
+		found = match[1] == version
+	}
+	return ""
+}
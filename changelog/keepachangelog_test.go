@@ -0,0 +1,59 @@
+package changelog
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func testRelease() *Release {
+	return &Release{
+		Version: "1.2.0",
+		Tag:     "v1.2.0",
+		Date:    time.Date(2020, 7, 4, 12, 0, 0, 0, time.UTC),
+		Sections: Sections(new(conventionalpulls.Config), []*conventionalpulls.PullRequest{
+			testPulls[1], testPulls[2], testPulls[4], testPulls[5],
+		}),
+	}
+}
+
+func TestUpdateKeepAChangelog(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.FromSlash("testdata/keepachangelog/*.input.md"))
+	require.NoError(t, err)
+	require.NotEmpty(t, inputs)
+	for _, input := range inputs {
+		input := input
+		name := strings.TrimSuffix(filepath.Base(input), ".input.md")
+		t.Run(name, func(t *testing.T) {
+			content, err := ioutil.ReadFile(input)
+			require.NoError(t, err)
+			got, err := UpdateKeepAChangelog(content, testRelease())
+			require.NoError(t, err)
+			golden := strings.TrimSuffix(input, ".input.md") + ".golden.md"
+			if *updateGolden {
+				require.NoError(t, ioutil.WriteFile(golden, got, 0o600))
+			}
+			want, err := ioutil.ReadFile(golden)
+			require.NoError(t, err)
+			require.Equal(t, string(want), string(got))
+
+			// running again for the same release changes nothing
+			again, err := UpdateKeepAChangelog(got, testRelease())
+			require.NoError(t, err)
+			require.Equal(t, string(got), string(again))
+		})
+	}
+}
+
+func TestRelease_tag(t *testing.T) {
+	require.Equal(t, "1.2.0", (&Release{Version: "1.2.0"}).tag())
+	require.Equal(t, "v1.2.0", (&Release{Version: "1.2.0", Tag: "v1.2.0"}).tag())
+}
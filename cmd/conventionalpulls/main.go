@@ -0,0 +1,710 @@
+// Command conventionalpulls computes release versions from the labels on GitHub pull requests.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/conventionalpulls/cache"
+	"github.com/willabides/conventionalpulls/changelog"
+	"github.com/willabides/conventionalpulls/configfile"
+	"github.com/willabides/conventionalpulls/conventionalcommits"
+	"github.com/willabides/conventionalpulls/github"
+	"github.com/willabides/octo-go"
+)
+
+// Exit codes
+const (
+	exitOK            = 0
+	exitError         = 1
+	exitUsage         = 2
+	exitMissingLabels = 3
+)
+
+const usage = `Usage: conventionalpulls <command> [flags]
+
+Commands:
+  next-version  print the next version for a release including the given pull requests
+  bump-level    print the version change (None, Patch, Minor or Major) for the given pull requests
+  explain       print a report of how the version change was decided (Markdown, or JSON with -format json)
+  check         exit with status 3 when any of the given pull requests has no configured label or, with
+                -strict-labels, has labels for different version changes. With -post, the result for one open
+                pull request is posted as a check run or commit status so unlabeled pull requests can't be merged
+  publish       tag a commit with the next version and create a GitHub release with notes from the pull requests
+
+Pull requests are given with -prs or found between -base and -head. The GitHub token is read from GITHUB_TOKEN.
+Options are read from .conventionalpulls.yml when it exists. Flags override config file values. When the config file
+sets tag_prefix, -prev may be a tag with the prefix and defaults to the latest tag with the prefix.
+Run "conventionalpulls <command> -h" for a command's flags.
+`
+
+func main() {
+	a := &app{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	os.Exit(a.run(context.Background(), os.Args[1:]))
+}
+
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	// clientOpts are added to the options for GitHub requests
+	clientOpts []octo.RequestOption
+}
+
+// commonFlags are the flags shared by all commands
+type commonFlags struct {
+	repo          string
+	prs           string
+	base          string
+	head          string
+	apiURL        string
+	format        string
+	requireLabels bool
+	strictLabels  bool
+	titles        bool
+	retries       int
+	cacheDir      string
+	cacheTTL      time.Duration
+	configFiles   stringsFlag
+
+	// set holds the names of flags that were set on the command line
+	set map[string]bool
+
+	// tags finds version tags with the config file's tag_prefix. It is set by app.config and is nil when tag_prefix
+	// isn't configured.
+	tags *conventionalpulls.TagResolver
+
+	// prFetcher fetches pull requests for release notes and posted checks with the same labels that
+	// cfg.PRLabelFetcher returns. It is set by app.config.
+	prFetcher conventionalpulls.PRFetcher
+}
+
+// stringsFlag is a flag.Value that can be set multiple times
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func (c *commonFlags) register(fs *flag.FlagSet, getenv func(string) string) {
+	fs.StringVar(&c.repo, "repo", getenv("GITHUB_REPOSITORY"), "GitHub repository as owner/repo. Default is $GITHUB_REPOSITORY")
+	fs.StringVar(&c.prs, "prs", "", "comma separated pull request numbers. Either -prs or -base and -head is required")
+	fs.StringVar(&c.base, "base", "", "find pull requests merged after this ref (usually the previous release tag)")
+	fs.StringVar(&c.head, "head", "", "find pull requests merged up to this ref")
+	fs.StringVar(&c.apiURL, "api-url", "", "GitHub API url. Default is https://api.github.com")
+	fs.StringVar(&c.format, "format", "text", "output format: text or json")
+	fs.BoolVar(&c.requireLabels, "require-labels", false, "fail when a pull request has no configured label")
+	fs.BoolVar(&c.strictLabels, "strict-labels", false, "fail when a pull request has labels for different version changes")
+	fs.BoolVar(&c.titles, "conventional-titles", false, "also classify pull requests by Conventional Commits titles such as \"feat(api)!: ...\"")
+	fs.IntVar(&c.retries, "retries", github.DefaultRetryPolicy.MaxRetries, "number of times to retry GitHub requests that fail with a transient error")
+	fs.StringVar(&c.cacheDir, "cache-dir", "", "cache pull request labels and GitHub responses in this directory between runs")
+	fs.DurationVar(&c.cacheTTL, "cache-ttl", 0, "how long cached labels are used before revalidating them with GitHub. The check command always revalidates")
+	fs.Var(&c.configFiles, "config", "config file to load. May be repeated to layer org defaults under repo config. Default is "+configfile.DefaultFilename+" when it exists")
+}
+
+type usageErr struct {
+	msg string
+}
+
+func (e *usageErr) Error() string {
+	return e.msg
+}
+
+func (c *commonFlags) validate() error {
+	switch {
+	case c.format != "text" && c.format != "json":
+		return &usageErr{msg: fmt.Sprintf("invalid -format %q", c.format)}
+	case strings.Count(c.repo, "/") != 1:
+		return &usageErr{msg: "-repo must be in the form owner/repo"}
+	case c.prs != "" && (c.base != "" || c.head != ""):
+		return &usageErr{msg: "-prs can't be used with -base or -head"}
+	case c.prs == "" && (c.base == "") != (c.head == ""):
+		return &usageErr{msg: "-base and -head must be used together"}
+	case c.prs == "" && c.base == "":
+		return &usageErr{msg: "either -prs or -base and -head are required"}
+	}
+	return nil
+}
+
+func (c *commonFlags) prIDs() ([]int, error) {
+	if c.prs == "" {
+		return []int{}, nil
+	}
+	parts := strings.Split(c.prs, ",")
+	ids := make([]int, len(parts))
+	for i, part := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, &usageErr{msg: fmt.Sprintf("invalid pull request number %q", part)}
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func (a *app) run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(a.stderr, usage)
+		return exitUsage
+	}
+	var err error
+	switch args[0] {
+	case "next-version":
+		err = a.nextVersion(ctx, args[1:])
+	case "bump-level":
+		err = a.bumpLevel(ctx, args[1:])
+	case "explain":
+		err = a.explain(ctx, args[1:])
+	case "check":
+		err = a.check(ctx, args[1:])
+	case "publish":
+		err = a.publish(ctx, args[1:])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(a.stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(a.stderr, "unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+	return a.exitCode(err)
+}
+
+func (a *app) exitCode(err error) int {
+	var uErr *usageErr
+	var missingErr *conventionalpulls.PRMissingLabelErr
+	var conflictErr *conventionalpulls.PRConflictingLabelErr
+	var configErr *configfile.ValidationErr
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &uErr), errors.As(err, &configErr):
+		fmt.Fprintln(a.stderr, err)
+		return exitUsage
+	case errors.As(err, &missingErr):
+		fmt.Fprintf(a.stderr, "%v: %s\n", err, joinInts(missingErr.IDs))
+		return exitMissingLabels
+	case errors.As(err, &conflictErr):
+		fmt.Fprintf(a.stderr, "%v:\n", err)
+		for _, id := range conflictErr.IDs {
+			fmt.Fprintf(a.stderr, "  #%d: %s\n", id, strings.Join(conflictErr.Labels[id], ", "))
+		}
+		return exitMissingLabels
+	default:
+		fmt.Fprintln(a.stderr, err)
+		return exitError
+	}
+}
+
+func (a *app) parseFlags(fs *flag.FlagSet, common *commonFlags, args []string) error {
+	fs.SetOutput(a.stderr)
+	common.register(fs, a.getenv)
+	err := fs.Parse(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageErr{msg: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return &usageErr{msg: fmt.Sprintf("unexpected argument %q", fs.Arg(0))}
+	}
+	common.set = map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		common.set[f.Name] = true
+	})
+	return common.validate()
+}
+
+func (a *app) clientOptions(common *commonFlags, store *cache.Store) ([]octo.RequestOption, error) {
+	var opts []octo.RequestOption
+	var transport http.RoundTripper
+	if common.retries > 0 {
+		transport = (&github.RetryPolicy{MaxRetries: common.retries}).Transport(nil)
+	}
+	if store != nil {
+		transport = store.Transport(transport)
+	}
+	if transport != nil {
+		opts = append(opts, octo.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+	if token := a.getenv("GITHUB_TOKEN"); token != "" {
+		opts = append(opts, octo.WithPATAuth(token))
+	}
+	if common.apiURL != "" {
+		u, err := url.Parse(common.apiURL)
+		if err != nil {
+			return nil, &usageErr{msg: fmt.Sprintf("invalid -api-url: %v", err)}
+		}
+		opts = append(opts, octo.WithBaseURL(*u))
+	}
+	return append(opts, a.clientOpts...), nil
+}
+
+// cacheRepoKey returns the repository's key in the label cache as host/owner/repo
+func (c *commonFlags) cacheRepoKey() string {
+	host := "github.com"
+	if u, err := url.Parse(c.apiURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return host + "/" + c.repo
+}
+
+// loadConfigFiles returns the merged -config files, or the default config file when it exists
+func (a *app) loadConfigFiles(common *commonFlags) (*configfile.File, error) {
+	filenames := common.configFiles
+	if len(filenames) == 0 {
+		_, err := os.Stat(configfile.DefaultFilename)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return &configfile.File{}, nil
+			}
+			return nil, err
+		}
+		filenames = []string{configfile.DefaultFilename}
+	}
+	files := make([]*configfile.File, len(filenames))
+	for i, filename := range filenames {
+		var err error
+		files[i], err = configfile.Load(filename)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return configfile.Merge(files...), nil
+}
+
+// config returns a Config and the pull request numbers for common
+func (a *app) config(ctx context.Context, common *commonFlags) (*conventionalpulls.Config, []int, error) {
+	var store *cache.Store
+	if common.cacheDir != "" {
+		store = cache.NewStore(common.cacheDir)
+	}
+	opts, err := a.clientOptions(common, store)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := a.loadConfigFiles(common)
+	if err != nil {
+		return nil, nil, err
+	}
+	owner, repo := splitRepo(common.repo)
+	cfg := &conventionalpulls.Config{
+		PRLabelFetcher: github.NewPRLabelFetcher(ctx, owner, repo, opts...),
+		PRDiscoverer:   github.NewPRDiscoverer(owner, repo, opts...),
+		Concurrency:    4,
+	}
+	file.Apply(cfg)
+	if file.TagPrefix != nil {
+		common.tags = &conventionalpulls.TagResolver{TagLister: github.NewTagLister(owner, repo, opts...)}
+		file.ApplyTagResolver(common.tags)
+	}
+	repoKey := common.cacheRepoKey()
+	common.prFetcher = github.NewPRFetcher(owner, repo, opts...)
+	if common.titles {
+		common.prFetcher, err = useConventionalTitles(cfg, common.prFetcher)
+		if err != nil {
+			return nil, nil, err
+		}
+		// title labels are cached apart from plain labels
+		repoKey += "#conventional-titles"
+	}
+	if store != nil {
+		cfg.PRLabelFetcher = cache.NewPRLabelFetcher(cfg.PRLabelFetcher, store, repoKey, common.cacheTTL)
+	}
+	if common.set["require-labels"] {
+		cfg.RequireLabels = common.requireLabels
+	}
+	if common.set["strict-labels"] {
+		cfg.StrictLabels = common.strictLabels
+	}
+	if common.base == "" {
+		var prIDs []int
+		prIDs, err = common.prIDs()
+		return cfg, prIDs, err
+	}
+	prIDs, err := cfg.PRDiscoverer.DiscoverPRs(ctx, common.base, common.head)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, prIDs, nil
+}
+
+// versionFlags are the flags for commands that calculate a version
+const prevUsage = "the previous version or tag. Required unless tag_prefix is configured, in which case the default is the latest tag"
+
+type versionFlags struct {
+	prev               string
+	preRelease         string
+	initialDevelopment bool
+}
+
+func (v *versionFlags) register(fs *flag.FlagSet, prevUsage string) {
+	fs.StringVar(&v.prev, "prev", "", prevUsage)
+	fs.StringVar(&v.preRelease, "pre-release", "", "pre-release identifier such as rc")
+	fs.BoolVar(&v.initialDevelopment, "initial-development", false, "while the major version is 0, breaking changes bump minor and minor changes bump patch")
+}
+
+// resolvePrev applies the config file's tag_prefix to -prev. A -prev that starts with the prefix is a tag, and the
+// prefix is removed. When -prev isn't set, it is the version of the latest tag with the prefix. Returns a usage error
+// when -prev is required and can't be resolved.
+func (v *versionFlags) resolvePrev(ctx context.Context, common *commonFlags, required bool) error {
+	switch {
+	case v.prev != "" && common.tags != nil:
+		v.prev = strings.TrimPrefix(v.prev, common.tags.Prefix)
+	case v.prev == "" && common.tags != nil:
+		var err error
+		_, v.prev, err = common.tags.LatestTag(ctx)
+		if err != nil {
+			return err
+		}
+	case v.prev == "" && required:
+		return &usageErr{msg: "-prev is required"}
+	}
+	return nil
+}
+
+func (v *versionFlags) apply(cfg *conventionalpulls.Config, common *commonFlags) {
+	if common.set["pre-release"] {
+		cfg.PreRelease = v.preRelease
+	}
+	if common.set["initial-development"] {
+		cfg.InitialDevelopment = v.initialDevelopment
+	}
+}
+
+// useConventionalTitles adds a label for each pull request's Conventional Commits title and a LabelRule matching it.
+// Returns a PRFetcher that adds the same label.
+func useConventionalTitles(
+	cfg *conventionalpulls.Config,
+	prFetcher conventionalpulls.PRFetcher,
+) (conventionalpulls.PRFetcher, error) {
+	labelNames := map[conventionalpulls.VersionChange]string{}
+	for _, change := range []conventionalpulls.VersionChange{
+		conventionalpulls.VersionChangeNone,
+		conventionalpulls.VersionChangePatch,
+		conventionalpulls.VersionChangeMinor,
+		conventionalpulls.VersionChangeMajor,
+	} {
+		name := "conventional-title:" + strings.ToLower(change.String())
+		rule, err := conventionalpulls.GlobLabelRule(name, change)
+		if err != nil {
+			return nil, err
+		}
+		labelNames[change] = name
+		cfg.LabelRules = append(cfg.LabelRules, rule)
+	}
+	cfg.PRLabelFetcher = conventionalcommits.NewPRLabelFetcher(prFetcher, nil, labelNames)
+	return conventionalcommits.NewPRFetcher(prFetcher, nil, labelNames), nil
+}
+
+func (a *app) nextVersion(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("next-version", flag.ContinueOnError)
+	var common commonFlags
+	var vf versionFlags
+	vf.register(fs, prevUsage)
+	err := a.parseFlags(fs, &common, args)
+	if err != nil {
+		return err
+	}
+	cfg, prIDs, err := a.config(ctx, &common)
+	if err != nil {
+		return err
+	}
+	err = vf.resolvePrev(ctx, &common, true)
+	if err != nil {
+		return err
+	}
+	vf.apply(cfg, &common)
+	bump, err := cfg.PRVersionChangeContext(ctx, prIDs...)
+	if err != nil {
+		return err
+	}
+	next, err := cfg.IncrementVersion(vf.prev, bump)
+	if err != nil {
+		return err
+	}
+	return a.output(common.format, next, map[string]interface{}{
+		"previous_version": vf.prev,
+		"next_version":     next,
+		"version_change":   bump.String(),
+		"pull_requests":    prIDs,
+	})
+}
+
+func (a *app) bumpLevel(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("bump-level", flag.ContinueOnError)
+	var common commonFlags
+	err := a.parseFlags(fs, &common, args)
+	if err != nil {
+		return err
+	}
+	cfg, prIDs, err := a.config(ctx, &common)
+	if err != nil {
+		return err
+	}
+	bump, err := cfg.PRVersionChangeContext(ctx, prIDs...)
+	if err != nil {
+		return err
+	}
+	return a.output(common.format, bump.String(), map[string]interface{}{
+		"version_change": bump.String(),
+		"pull_requests":  prIDs,
+	})
+}
+
+func (a *app) explain(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	var common commonFlags
+	var vf versionFlags
+	vf.register(fs, "the previous version or tag. When it is set or found with tag_prefix, the report includes the next version")
+	err := a.parseFlags(fs, &common, args)
+	if err != nil {
+		return err
+	}
+	cfg, prIDs, err := a.config(ctx, &common)
+	if err != nil {
+		return err
+	}
+	err = vf.resolvePrev(ctx, &common, false)
+	if err != nil {
+		return err
+	}
+	vf.apply(cfg, &common)
+	report, err := cfg.Explain(ctx, vf.prev, prIDs...)
+	if err != nil {
+		return err
+	}
+	if common.format == "json" {
+		return a.output(common.format, "", report)
+	}
+	return report.Markdown(a.stdout)
+}
+
+func (a *app) check(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	var common commonFlags
+	var post, detailsURL string
+	fs.StringVar(&post, "post", "", "post the result for a single open pull request as a GitHub check run (check-run) or commit status (status)")
+	fs.StringVar(&detailsURL, "details-url", "", "url linked from the posted check run or commit status")
+	err := a.parseFlags(fs, &common, args)
+	if err != nil {
+		return err
+	}
+	if post != "" {
+		return a.postCheck(ctx, &common, post, detailsURL)
+	}
+	// labels can change right before a merge, so check never uses cached labels without revalidating them
+	common.cacheTTL = 0
+	cfg, prIDs, err := a.config(ctx, &common)
+	if err != nil {
+		return err
+	}
+	cfg.RequireLabels = true
+	_, err = cfg.PRVersionChangeContext(ctx, prIDs...)
+	var missingErr *conventionalpulls.PRMissingLabelErr
+	var conflictErr *conventionalpulls.PRConflictingLabelErr
+	switch {
+	case err == nil:
+		return a.output(common.format, "ok", map[string]interface{}{
+			"ok":                 true,
+			"missing_labels":     []int{},
+			"conflicting_labels": map[int][]string{},
+		})
+	case common.format != "json":
+		return err
+	case errors.As(err, &missingErr):
+		return a.checkFailure(err, map[string]interface{}{
+			"ok":                 false,
+			"missing_labels":     missingErr.IDs,
+			"conflicting_labels": map[int][]string{},
+		})
+	case errors.As(err, &conflictErr):
+		return a.checkFailure(err, map[string]interface{}{
+			"ok":                 false,
+			"missing_labels":     []int{},
+			"conflicting_labels": conflictErr.Labels,
+		})
+	default:
+		return err
+	}
+}
+
+// postCheck checks the labels of a single open pull request and posts the result to its head commit
+func (a *app) postCheck(ctx context.Context, common *commonFlags, post, detailsURL string) error {
+	if post != "check-run" && post != "status" {
+		return &usageErr{msg: fmt.Sprintf("invalid -post %q", post)}
+	}
+	prIDs, err := common.prIDs()
+	if err != nil {
+		return err
+	}
+	if len(prIDs) != 1 {
+		return &usageErr{msg: "-post requires exactly one pull request in -prs"}
+	}
+	cfg, _, err := a.config(ctx, common)
+	if err != nil {
+		return err
+	}
+	opts, err := a.clientOptions(common, nil)
+	if err != nil {
+		return err
+	}
+	owner, repo := splitRepo(common.repo)
+	checker := github.NewLabelChecker(cfg, owner, repo, opts...)
+	checker.DetailsURL = detailsURL
+	if common.titles {
+		checker.PRFetcher = common.prFetcher
+	}
+	result, err := checker.Check(ctx, prIDs[0])
+	if err != nil {
+		return err
+	}
+	if post == "status" {
+		err = checker.PostStatus(ctx, result)
+	} else {
+		err = checker.PostCheckRun(ctx, result)
+	}
+	if err != nil {
+		return err
+	}
+	err = a.output(common.format, result.Title, map[string]interface{}{
+		"ok":           result.Passed,
+		"pull_request": result.PullRequest,
+		"head_sha":     result.HeadSHA,
+		"conclusion":   result.Conclusion(),
+		"title":        result.Title,
+	})
+	if err != nil {
+		return err
+	}
+	return result.Err
+}
+
+func (a *app) publish(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ContinueOnError)
+	var common commonFlags
+	var vf versionFlags
+	vf.register(fs, prevUsage)
+	sha := fs.String("sha", a.getenv("GITHUB_SHA"), "the commit to tag. Default is $GITHUB_SHA")
+	draft := fs.Bool("draft", false, "create a draft release")
+	err := a.parseFlags(fs, &common, args)
+	if err != nil {
+		return err
+	}
+	if *sha == "" {
+		return &usageErr{msg: "-sha is required"}
+	}
+	cfg, prIDs, err := a.config(ctx, &common)
+	if err != nil {
+		return err
+	}
+	err = vf.resolvePrev(ctx, &common, true)
+	if err != nil {
+		return err
+	}
+	vf.apply(cfg, &common)
+	next, err := cfg.NextVersionContext(ctx, vf.prev, prIDs...)
+	if err != nil {
+		return err
+	}
+	nextVersion, err := semver.NewVersion(next)
+	if err != nil {
+		return err
+	}
+	opts, err := a.clientOptions(&common, nil)
+	if err != nil {
+		return err
+	}
+	owner, repo := splitRepo(common.repo)
+	generator := &changelog.Generator{
+		Config:    cfg,
+		PRFetcher: common.prFetcher,
+	}
+	var notes strings.Builder
+	err = generator.Generate(ctx, &notes, next, prIDs...)
+	if err != nil {
+		return err
+	}
+	tag := next
+	if common.tags != nil {
+		tag = common.tags.Prefix + next
+	}
+	release, err := github.NewReleasePublisher(owner, repo, opts...).Publish(ctx, &github.Release{
+		Tag:        tag,
+		SHA:        *sha,
+		Notes:      notes.String(),
+		Draft:      *draft,
+		Prerelease: nextVersion.Prerelease() != "",
+	})
+	if err != nil {
+		return err
+	}
+	text := release.URL
+	if release.Existing {
+		text += " (already exists)"
+	}
+	return a.output(common.format, text, map[string]interface{}{
+		"version":    release.Tag,
+		"url":        release.URL,
+		"draft":      release.Draft,
+		"prerelease": release.Prerelease,
+		"existing":   release.Existing,
+	})
+}
+
+// checkFailure writes value as JSON and returns err
+func (a *app) checkFailure(err error, value interface{}) error {
+	outErr := a.output("json", "", value)
+	if outErr != nil {
+		return outErr
+	}
+	return err
+}
+
+// output writes text or value as JSON depending on format
+func (a *app) output(format, text string, value interface{}) error {
+	if format == "json" {
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+	_, err := fmt.Fprintln(a.stdout, text)
+	return err
+}
+
+func splitRepo(ownerRepo string) (owner, repo string) {
+	parts := strings.SplitN(ownerRepo, "/", 2)
+	return parts[0], parts[1]
+}
+
+func joinInts(ints []int) string {
+	strs := make([]string, len(ints))
+	for i, n := range ints {
+		strs[i] = "#" + strconv.Itoa(n)
+	}
+	return strings.Join(strs, ", ")
+}
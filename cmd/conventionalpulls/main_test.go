@@ -0,0 +1,428 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/octo-go"
+	"github.com/willabides/octo-go/components"
+	"github.com/willabides/octo-go/octotest"
+)
+
+func testServer(pulls map[int][]string) *octotest.Server {
+	server := octotest.New()
+	for id, labels := range pulls {
+		body := &octo.PullsGetResponseBody{Number: int64(id), Title: testTitles[id]}
+		for _, label := range labels {
+			body.Labels = append(body.Labels, components.PullRequestLabelsItem{Name: label})
+		}
+		server.Expect(&octo.PullsGetReq{
+			Owner:      "foo",
+			Repo:       "bar",
+			PullNumber: int64(id),
+		}, octotest.JSONResponder(200, body))
+	}
+	return server
+}
+
+var testTitles = map[int]string{
+	6: "feat(api): add widgets",
+	7: "fix!: remove widgets",
+}
+
+// expectPublish adds the requests for publishing a new release of tag for commit abc123
+func expectPublish(server *octotest.Server, tag, notes string, draft, prerelease bool) {
+	server.Expect(&octo.ReposListReleasesReq{
+		Owner:   "foo",
+		Repo:    "bar",
+		PerPage: octo.Int64(100),
+	}, octotest.JSONResponder(200, []components.Release2{}))
+	server.Expect(&octo.GitGetRefReq{
+		Owner: "foo",
+		Repo:  "bar",
+		Ref:   "tags/" + tag,
+	}, octotest.JSONResponder(404, map[string]string{"message": "Not Found"}))
+	server.Expect(&octo.GitCreateTagReq{
+		Owner: "foo",
+		Repo:  "bar",
+		RequestBody: octo.GitCreateTagReqBody{
+			Tag:     octo.String(tag),
+			Message: octo.String(tag),
+			Object:  octo.String("abc123"),
+			Type:    octo.String("commit"),
+		},
+	}, octotest.JSONResponder(201, &components.GitTag{Sha: "tagsha"}))
+	server.Expect(&octo.GitCreateRefReq{
+		Owner: "foo",
+		Repo:  "bar",
+		RequestBody: octo.GitCreateRefReqBody{
+			Ref: octo.String("refs/tags/" + tag),
+			Sha: octo.String("tagsha"),
+		},
+	}, octotest.JSONResponder(201, &components.GitRef{}))
+	server.Expect(&octo.ReposCreateReleaseReq{
+		Owner: "foo",
+		Repo:  "bar",
+		RequestBody: octo.ReposCreateReleaseReqBody{
+			TagName:         octo.String(tag),
+			TargetCommitish: octo.String("abc123"),
+			Name:            octo.String(tag),
+			Body:            octo.String(notes),
+			Draft:           octo.Bool(draft),
+			Prerelease:      octo.Bool(prerelease),
+		},
+	}, octotest.JSONResponder(201, &components.Release{
+		TagName:    tag,
+		HtmlUrl:    "https://github.com/foo/bar/releases/tag/" + tag,
+		Draft:      draft,
+		Prerelease: prerelease,
+	}))
+}
+
+func runApp(t *testing.T, server *octotest.Server, env map[string]string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var outBuf, errBuf bytes.Buffer
+	a := &app{
+		stdout: &outBuf,
+		stderr: &errBuf,
+		getenv: func(key string) string {
+			return env[key]
+		},
+		clientOpts: server.Client(),
+	}
+	code = a.run(context.Background(), args)
+	return code, outBuf.String(), errBuf.String()
+}
+
+func Test_app(t *testing.T) {
+	server := testServer(map[int][]string{
+		1: {"Patch"},
+		2: {"Minor Change"},
+		3: {"documentation"},
+		5: {"Patch", "Breaking Change"},
+		6: {},
+		7: {"Patch"},
+	})
+
+	t.Run("next-version", func(t *testing.T) {
+		code, stdout, stderr := runApp(t, server, nil, "next-version", "-repo", "foo/bar", "-prev", "v1.2.3", "-prs", "1,2")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "v1.3.0\n", stdout)
+	})
+
+	t.Run("next-version json", func(t *testing.T) {
+		env := map[string]string{"GITHUB_REPOSITORY": "foo/bar"}
+		code, stdout, stderr := runApp(t, server, env, "next-version", "-prev", "v1.2.3", "-prs", "1", "-pre-release", "rc", "-format", "json")
+		require.Equal(t, exitOK, code, stderr)
+		require.JSONEq(t, `{
+  "previous_version": "v1.2.3",
+  "next_version": "v1.2.4-rc.1",
+  "version_change": "Patch",
+  "pull_requests": [1]
+}`, stdout)
+	})
+
+	t.Run("next-version fetches labels once", func(t *testing.T) {
+		server := octotest.New()
+		fetches := 0
+		responder := octotest.JSONResponder(200, &octo.PullsGetResponseBody{
+			Number: 1,
+			Labels: []components.PullRequestLabelsItem{{Name: "Patch"}},
+		})
+		server.Expect(&octo.PullsGetReq{Owner: "foo", Repo: "bar", PullNumber: 1}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches++
+			responder.ServeHTTP(w, r)
+		}))
+		code, stdout, stderr := runApp(t, server, nil, "next-version", "-repo", "foo/bar", "-prev", "v1.2.3", "-prs", "1")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "v1.2.4\n", stdout)
+		require.Equal(t, 1, fetches)
+	})
+
+	t.Run("next-version without prev", func(t *testing.T) {
+		code, _, stderr := runApp(t, server, nil, "next-version", "-repo", "foo/bar", "-prs", "1")
+		require.Equal(t, exitUsage, code)
+		require.Equal(t, "-prev is required\n", stderr)
+	})
+
+	t.Run("bump-level", func(t *testing.T) {
+		code, stdout, stderr := runApp(t, server, nil, "bump-level", "-repo", "foo/bar", "-prs", "1, 2")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "Minor\n", stdout)
+	})
+
+	t.Run("bump-level missing labels", func(t *testing.T) {
+		code, _, stderr := runApp(t, server, nil, "bump-level", "-repo", "foo/bar", "-prs", "1,3", "-require-labels")
+		require.Equal(t, exitMissingLabels, code)
+		require.Contains(t, stderr, "#3")
+	})
+
+	t.Run("explain", func(t *testing.T) {
+		code, stdout, stderr := runApp(t, server, nil, "explain", "-repo", "foo/bar", "-prev", "v1.2.3", "-prs", "1,2")
+		require.Equal(t, exitOK, code, stderr)
+		require.Contains(t, stdout, "Next version: v1.3.0\n")
+		require.Contains(t, stdout, "Version change: **Minor** (driven by #2)\n")
+		require.Contains(t, stdout, "| #2 | minor change | minor change: label `minor change` → Minor | Minor |\n")
+	})
+
+	t.Run("explain json", func(t *testing.T) {
+		code, stdout, stderr := runApp(t, server, nil, "explain", "-repo", "foo/bar", "-prs", "3", "-format", "json")
+		require.Equal(t, exitOK, code, stderr)
+		require.JSONEq(t, `{
+  "version_change": "None",
+  "driving_prs": [],
+  "pull_requests": [
+    {"id": 3, "labels": ["documentation"], "matches": [], "version_change": "None"}
+  ],
+  "driving_commits": [],
+  "commits": []
+}`, stdout)
+	})
+
+	t.Run("conventional titles", func(t *testing.T) {
+		code, stdout, stderr := runApp(t, server, nil, "bump-level", "-repo", "foo/bar", "-prs", "1,6", "-conventional-titles", "-require-labels")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "Minor\n", stdout)
+
+		code, stdout, stderr = runApp(t, server, nil, "bump-level", "-repo", "foo/bar", "-prs", "6,7", "-conventional-titles")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "Major\n", stdout)
+
+		code, _, _ = runApp(t, server, nil, "check", "-repo", "foo/bar", "-prs", "6")
+		require.Equal(t, exitMissingLabels, code)
+	})
+
+	t.Run("check", func(t *testing.T) {
+		code, stdout, stderr := runApp(t, server, nil, "check", "-repo", "foo/bar", "-prs", "1,2")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "ok\n", stdout)
+	})
+
+	t.Run("check json failure", func(t *testing.T) {
+		code, stdout, _ := runApp(t, server, nil, "check", "-repo", "foo/bar", "-prs", "1,3", "-format", "json")
+		require.Equal(t, exitMissingLabels, code)
+		require.JSONEq(t, `{"ok": false, "missing_labels": [3], "conflicting_labels": {}}`, stdout)
+	})
+
+	t.Run("check strict labels", func(t *testing.T) {
+		code, stdout, stderr := runApp(t, server, nil, "check", "-repo", "foo/bar", "-prs", "1,5")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "ok\n", stdout)
+
+		code, _, stderr = runApp(t, server, nil, "check", "-repo", "foo/bar", "-prs", "1,5", "-strict-labels")
+		require.Equal(t, exitMissingLabels, code)
+		require.Equal(t, "one or more PRs have conflicting labels:\n  #5: patch, breaking change\n", stderr)
+
+		code, stdout, _ = runApp(t, server, nil, "check", "-repo", "foo/bar", "-prs", "1,5", "-strict-labels", "-format", "json")
+		require.Equal(t, exitMissingLabels, code)
+		require.JSONEq(t, `{"ok": false, "missing_labels": [], "conflicting_labels": {"5": ["patch", "breaking change"]}}`, stdout)
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		code, _, stderr := runApp(t, server, nil, "bump-level", "-repo", "foo/bar", "-prs", "4")
+		require.Equal(t, exitError, code)
+		require.NotEmpty(t, stderr)
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, os.RemoveAll(dir))
+		})
+		code, stdout, stderr := runApp(t, server, nil, "bump-level", "-repo", "foo/bar", "-prs", "1,2", "-cache-dir", dir)
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "Minor\n", stdout)
+
+		// with a ttl, the second run uses cached labels, so the empty server isn't called
+		code, stdout, stderr = runApp(t, testServer(nil), nil, "bump-level", "-repo", "foo/bar", "-prs", "1,2", "-cache-dir", dir, "-cache-ttl", "1h")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "Minor\n", stdout)
+
+		// by default, cached labels are revalidated
+		code, _, _ = runApp(t, testServer(nil), nil, "bump-level", "-repo", "foo/bar", "-prs", "1,2", "-cache-dir", dir)
+		require.Equal(t, exitError, code)
+
+		// check always revalidates
+		code, _, _ = runApp(t, testServer(nil), nil, "check", "-repo", "foo/bar", "-prs", "1,2", "-cache-dir", dir, "-cache-ttl", "1h")
+		require.Equal(t, exitError, code)
+
+		// conventional title labels are cached too
+		code, stdout, stderr = runApp(t, server, nil, "bump-level", "-repo", "foo/bar", "-prs", "6,7", "-conventional-titles", "-cache-dir", dir)
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "Major\n", stdout)
+		code, stdout, stderr = runApp(t, testServer(nil), nil, "bump-level", "-repo", "foo/bar", "-prs", "6,7", "-conventional-titles", "-cache-dir", dir, "-cache-ttl", "1h")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "Major\n", stdout)
+	})
+
+	t.Run("config files", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, os.RemoveAll(dir))
+		})
+		orgConfig := filepath.Join(dir, "org.yml")
+		err = ioutil.WriteFile(orgConfig, []byte("labels:\n  documentation: patch\npre_release: beta\n"), 0o600)
+		require.NoError(t, err)
+		repoConfig := filepath.Join(dir, "repo.json")
+		err = ioutil.WriteFile(repoConfig, []byte(`{"labels": {"Patch": "minor"}}`), 0o600)
+		require.NoError(t, err)
+
+		code, stdout, stderr := runApp(t, server, nil, "next-version", "-repo", "foo/bar", "-prev", "v1.2.3", "-prs", "3",
+			"-config", orgConfig)
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "v1.2.4-beta.1\n", stdout)
+
+		code, stdout, stderr = runApp(t, server, nil, "next-version", "-repo", "foo/bar", "-prev", "v1.2.3", "-prs", "1",
+			"-config", orgConfig, "-config", repoConfig, "-pre-release", "")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "v1.3.0\n", stdout)
+
+		badConfig := filepath.Join(dir, "bad.yml")
+		err = ioutil.WriteFile(badConfig, []byte("labels:\n  bug: huge\n"), 0o600)
+		require.NoError(t, err)
+		code, _, stderr = runApp(t, server, nil, "bump-level", "-repo", "foo/bar", "-prs", "1", "-config", badConfig)
+		require.Equal(t, exitUsage, code)
+		require.Contains(t, stderr, "bad.yml:2:8: ")
+	})
+
+	t.Run("tag prefix", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, os.RemoveAll(dir))
+		})
+		config := filepath.Join(dir, "config.yml")
+		err = ioutil.WriteFile(config, []byte("tag_prefix: mymod/v\n"), 0o600)
+		require.NoError(t, err)
+		server := testServer(map[int][]string{1: {"Patch"}, 2: {"Minor Change"}})
+		server.Expect(&octo.ReposListTagsReq{
+			Owner:   "foo",
+			Repo:    "bar",
+			PerPage: octo.Int64(100),
+		}, octotest.JSONResponder(200, []components.Tag{{Name: "v2.0.0"}, {Name: "mymod/v1.2.3"}, {Name: "mymod/v1.1.0"}}))
+
+		code, stdout, stderr := runApp(t, server, nil, "next-version", "-repo", "foo/bar", "-prs", "1", "-config", config)
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "1.2.4\n", stdout)
+
+		code, stdout, stderr = runApp(t, server, nil, "next-version", "-repo", "foo/bar", "-prev", "mymod/v1.1.0", "-prs", "2", "-config", config)
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "1.2.0\n", stdout)
+
+		code, stdout, stderr = runApp(t, server, nil, "explain", "-repo", "foo/bar", "-prs", "2", "-config", config)
+		require.Equal(t, exitOK, code, stderr)
+		require.Contains(t, stdout, "Previous version: 1.2.3\nNext version: 1.3.0\n")
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		server := testServer(map[int][]string{2: {"Minor Change"}})
+		expectPublish(server, "v1.3.0-rc.1", "## v1.3.0-rc.1\n\n### Features\n\n-  (#2)\n", true, true)
+		env := map[string]string{"GITHUB_SHA": "abc123"}
+		code, stdout, stderr := runApp(t, server, env, "publish", "-repo", "foo/bar", "-prev", "v1.2.3", "-prs", "2", "-pre-release", "rc", "-draft")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "https://github.com/foo/bar/releases/tag/v1.3.0-rc.1\n", stdout)
+	})
+
+	t.Run("publish with conventional titles", func(t *testing.T) {
+		server := testServer(map[int][]string{6: {}})
+		expectPublish(server, "v1.3.0", "## v1.3.0\n\n### Features\n\n- feat(api): add widgets (#6)\n", false, false)
+		env := map[string]string{"GITHUB_SHA": "abc123"}
+		code, stdout, stderr := runApp(t, server, env, "publish", "-repo", "foo/bar", "-prev", "v1.2.3", "-prs", "6", "-conventional-titles")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "https://github.com/foo/bar/releases/tag/v1.3.0\n", stdout)
+	})
+
+	t.Run("publish with tag prefix", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, os.RemoveAll(dir))
+		})
+		config := filepath.Join(dir, "config.yml")
+		err = ioutil.WriteFile(config, []byte("tag_prefix: mymod/v\n"), 0o600)
+		require.NoError(t, err)
+		server := testServer(map[int][]string{2: {"Minor Change"}})
+		server.Expect(&octo.ReposListTagsReq{
+			Owner:   "foo",
+			Repo:    "bar",
+			PerPage: octo.Int64(100),
+		}, octotest.JSONResponder(200, []components.Tag{{Name: "v2.0.0"}, {Name: "mymod/v1.2.3"}}))
+		expectPublish(server, "mymod/v1.3.0", "## 1.3.0\n\n### Features\n\n-  (#2)\n", false, false)
+		env := map[string]string{"GITHUB_SHA": "abc123"}
+		code, stdout, stderr := runApp(t, server, env, "publish", "-repo", "foo/bar", "-prs", "2", "-config", config, "-format", "json")
+		require.Equal(t, exitOK, code, stderr)
+		require.JSONEq(t, `{
+  "version": "mymod/v1.3.0",
+  "url": "https://github.com/foo/bar/releases/tag/mymod/v1.3.0",
+  "draft": false,
+  "prerelease": false,
+  "existing": false
+}`, stdout)
+	})
+
+	t.Run("check post", func(t *testing.T) {
+		server := testServer(map[int][]string{3: {"documentation"}})
+		server.Expect(&octo.ReposCreateCommitStatusReq{
+			Owner: "foo",
+			Repo:  "bar",
+			RequestBody: octo.ReposCreateCommitStatusReqBody{
+				State:       octo.String("failure"),
+				Context:     octo.String("conventionalpulls/labels"),
+				Description: octo.String("Missing a version label"),
+				TargetUrl:   octo.String("https://example.com/labels"),
+			},
+		}, octotest.JSONResponder(201, &components.Status{}))
+		code, stdout, stderr := runApp(t, server, nil, "check", "-repo", "foo/bar", "-prs", "3", "-post", "status", "-details-url", "https://example.com/labels")
+		require.Equal(t, exitMissingLabels, code)
+		require.Equal(t, "Missing a version label\n", stdout)
+		require.Equal(t, "one or more PRs have no configured labels: #3\n", stderr)
+	})
+
+	t.Run("check post with conventional titles", func(t *testing.T) {
+		server := testServer(map[int][]string{6: {}})
+		server.Expect(&octo.ReposCreateCommitStatusReq{
+			Owner: "foo",
+			Repo:  "bar",
+			RequestBody: octo.ReposCreateCommitStatusReqBody{
+				State:       octo.String("success"),
+				Context:     octo.String("conventionalpulls/labels"),
+				Description: octo.String("Labeled for a minor version change"),
+			},
+		}, octotest.JSONResponder(201, &components.Status{}))
+		code, stdout, stderr := runApp(t, server, nil, "check", "-repo", "foo/bar", "-prs", "6", "-post", "status", "-conventional-titles")
+		require.Equal(t, exitOK, code, stderr)
+		require.Equal(t, "Labeled for a minor version change\n", stdout)
+	})
+
+	t.Run("usage errors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{},
+			{"nope"},
+			{"bump-level", "-prs", "1"},
+			{"bump-level", "-repo", "foo/bar", "-prs", "x"},
+			{"bump-level", "-repo", "foo/bar", "-prs", "1", "-base", "v1.0.0"},
+			{"bump-level", "-repo", "foo/bar", "-base", "v1.0.0"},
+			{"bump-level", "-repo", "foo/bar", "-format", "yaml"},
+			{"next-version", "-repo", "foo/bar", "-prev", "v1.2.3"},
+			{"check", "-repo", "foo/bar"},
+			{"explain", "-repo", "foo/bar", "-prev", "v1.2.3"},
+			{"bump-level", "-repo", "foo/bar", "extra"},
+			{"bump-level", "-nope"},
+			{"publish", "-repo", "foo/bar", "-prs", "1", "-sha", "abc"},
+			{"publish", "-repo", "foo/bar", "-prs", "1", "-prev", "v1.2.3"},
+			{"check", "-repo", "foo/bar", "-prs", "1", "-post", "comment"},
+			{"check", "-repo", "foo/bar", "-prs", "1,2", "-post", "status"},
+		} {
+			code, _, _ := runApp(t, server, nil, args...)
+			require.Equal(t, exitUsage, code, "%q", args)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package conventionalpulls
+
+import "context"
+
+// Commit is a git commit
+type Commit struct {
+	SHA     string
+	Message string
+}
+
+// Subject returns the first line of the commit message
+func (c *Commit) Subject() string {
+	for i, r := range c.Message {
+		if r == '\n' {
+			return c.Message[:i]
+		}
+	}
+	return c.Message
+}
+
+// CommitLister lists the commits between two git refs
+type CommitLister interface {
+	// ListCommits returns the commits reachable from headRef but not baseRef, oldest first
+	ListCommits(ctx context.Context, baseRef, headRef string) (commits []Commit, err error)
+}
@@ -0,0 +1,19 @@
+package conventionalpulls
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommit_Subject(t *testing.T) {
+	for message, want := range map[string]string{
+		"":                               "",
+		"feat: add widgets":              "feat: add widgets",
+		"feat: add widgets\n":            "feat: add widgets",
+		"feat: add widgets\n\nRefs #123": "feat: add widgets",
+	} {
+		commit := Commit{Message: message}
+		require.Equal(t, want, commit.Subject(), message)
+	}
+}
@@ -0,0 +1,442 @@
+// Package configfile loads conventionalpulls configuration from .conventionalpulls.yml (or JSON) files.
+//
+// A config file looks like this:
+//
+//	labels:
+//	  breaking: major
+//	  enhancement: minor
+//	  bug: patch
+//	  documentation: none
+//	label_rules:
+//	  - glob: "semver[:/]major"
+//	    change: major
+//	  - regexp: '^type:\s*feature$'
+//	    change: minor
+//	require_labels: true
+//	strict_labels: true
+//	tag_prefix: v
+//	pre_release: rc
+//	initial_development: false
+//	concurrency: 4
+//
+// All keys are optional. When labels is set, it replaces the default label values. A label_rules entry has either a
+// glob or a regexp pattern. Labels listed in labels take precedence over label_rules, and the first matching rule
+// applies. See conventionalpulls.GlobLabelRule and conventionalpulls.RegexpLabelRule for pattern syntax.
+package configfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/willabides/conventionalpulls"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFilename is the name of a repository's config file
+const DefaultFilename = ".conventionalpulls.yml"
+
+var preReleaseExp = regexp.MustCompile(`^[0-9A-Za-z-]+$`)
+
+// File is a parsed config file. Fields are nil when they aren't set in the file.
+type File struct {
+	// Filename is the file's path. It is used in error messages.
+	Filename string
+
+	Labels             map[string]conventionalpulls.VersionChange
+	LabelRules         []*conventionalpulls.LabelRule
+	RequireLabels      *bool
+	StrictLabels       *bool
+	TagPrefix          *string
+	PreRelease         *string
+	InitialDevelopment *bool
+	Concurrency        *int
+}
+
+// Load reads and parses the config file at filename
+func Load(filename string) (*File, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(filename, data)
+}
+
+// Parse parses the content of a config file. Files with a ".json" extension must be valid JSON. Returns a
+// *ValidationErr when data isn't a valid config.
+func Parse(filename string, data []byte) (*File, error) {
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		err := checkJSON(filename, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var doc yaml.Node
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, &ValidationErr{
+			Filename: filename,
+			Msg:      strings.TrimPrefix(err.Error(), "yaml: "),
+		}
+	}
+	p := &parser{
+		file: &File{Filename: filename},
+	}
+	if len(doc.Content) == 0 {
+		return p.file, nil
+	}
+	err = p.parseRoot(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	return p.file, nil
+}
+
+// checkJSON returns a *ValidationErr positioned at the first syntax error in data
+func checkJSON(filename string, data []byte) error {
+	var v interface{}
+	err := json.Unmarshal(data, &v)
+	if err == nil {
+		return nil
+	}
+	vErr := &ValidationErr{
+		Filename: filename,
+		Msg:      err.Error(),
+	}
+	if syntaxErr, ok := err.(*json.SyntaxError); ok {
+		vErr.Line, vErr.Column = offsetPosition(data, syntaxErr.Offset)
+	}
+	return vErr
+}
+
+// offsetPosition returns the line and column for a byte offset
+func offsetPosition(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line, column = 1, 1
+	for _, b := range data[:offset] {
+		column++
+		if b == '\n' {
+			line++
+			column = 1
+		}
+	}
+	return line, column
+}
+
+type parser struct {
+	file *File
+}
+
+func (p *parser) errorf(node *yaml.Node, format string, args ...interface{}) error {
+	return &ValidationErr{
+		Filename: p.file.Filename,
+		Line:     node.Line,
+		Column:   node.Column,
+		Msg:      fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) parseRoot(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return p.errorf(node, "config must be a mapping")
+	}
+	return p.eachPair(node, func(key, value *yaml.Node) error {
+		switch key.Value {
+		case "labels":
+			return p.parseLabels(value)
+		case "label_rules":
+			return p.parseLabelRules(value)
+		case "require_labels":
+			return p.parseBool(value, &p.file.RequireLabels)
+		case "strict_labels":
+			return p.parseBool(value, &p.file.StrictLabels)
+		case "initial_development":
+			return p.parseBool(value, &p.file.InitialDevelopment)
+		case "tag_prefix":
+			return p.parseString(value, &p.file.TagPrefix)
+		case "pre_release":
+			err := p.parseString(value, &p.file.PreRelease)
+			if err != nil {
+				return err
+			}
+			if *p.file.PreRelease != "" && !preReleaseExp.MatchString(*p.file.PreRelease) {
+				return p.errorf(value, "invalid pre_release %q", *p.file.PreRelease)
+			}
+			return nil
+		case "concurrency":
+			return p.parseConcurrency(value)
+		default:
+			return p.errorf(key, "unknown key %q", key.Value)
+		}
+	})
+}
+
+// eachPair calls fn for each key/value pair of a mapping node. Keys must be unique strings.
+func (p *parser) eachPair(node *yaml.Node, fn func(key, value *yaml.Node) error) error {
+	seen := make(map[string]bool, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Kind != yaml.ScalarNode || key.Tag != "!!str" {
+			return p.errorf(key, "keys must be strings")
+		}
+		if seen[key.Value] {
+			return p.errorf(key, "duplicate key %q", key.Value)
+		}
+		seen[key.Value] = true
+		err := fn(key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseLabels(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return p.errorf(node, "labels must be a mapping of label names to version changes")
+	}
+	labels := make(map[string]conventionalpulls.VersionChange, len(node.Content)/2)
+	lowerNames := make(map[string]bool, len(node.Content)/2)
+	err := p.eachPair(node, func(key, value *yaml.Node) error {
+		if lowerNames[strings.ToLower(key.Value)] {
+			return p.errorf(key, "duplicate label %q", key.Value)
+		}
+		lowerNames[strings.ToLower(key.Value)] = true
+		change, err := p.parseVersionChange(value, fmt.Sprintf("version change for label %q", key.Value))
+		if err != nil {
+			return err
+		}
+		labels[key.Value] = change
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	p.file.Labels = labels
+	return nil
+}
+
+// parseVersionChange parses a version change. what describes the value in error messages.
+func (p *parser) parseVersionChange(node *yaml.Node, what string) (conventionalpulls.VersionChange, error) {
+	if node.Kind == yaml.ScalarNode {
+		change, err := conventionalpulls.ParseVersionChange(node.Value)
+		if err == nil {
+			return change, nil
+		}
+	}
+	return 0, p.errorf(node, "%s must be one of none, patch, minor or major", what)
+}
+
+func (p *parser) parseLabelRules(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return p.errorf(node, "label_rules must be a list")
+	}
+	rules := make([]*conventionalpulls.LabelRule, 0, len(node.Content))
+	for _, item := range node.Content {
+		rule, err := p.parseLabelRule(item)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+	p.file.LabelRules = rules
+	return nil
+}
+
+func (p *parser) parseLabelRule(node *yaml.Node) (*conventionalpulls.LabelRule, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, p.errorf(node, "label rule must be a mapping with a pattern and change")
+	}
+	var patternNode, changeNode *yaml.Node
+	var newRule func(string, conventionalpulls.VersionChange) (*conventionalpulls.LabelRule, error)
+	err := p.eachPair(node, func(key, value *yaml.Node) error {
+		switch key.Value {
+		case "glob", "regexp":
+			if patternNode != nil {
+				return p.errorf(key, "label rule must have only one of glob or regexp")
+			}
+			if value.Kind != yaml.ScalarNode || value.Tag != "!!str" {
+				return p.errorf(value, "expected a string")
+			}
+			patternNode = value
+			newRule = conventionalpulls.GlobLabelRule
+			if key.Value == "regexp" {
+				newRule = conventionalpulls.RegexpLabelRule
+			}
+		case "change":
+			changeNode = value
+		default:
+			return p.errorf(key, "unknown key %q", key.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if patternNode == nil {
+		return nil, p.errorf(node, "label rule must have a glob or regexp")
+	}
+	if changeNode == nil {
+		return nil, p.errorf(node, "label rule must have a change")
+	}
+	change, err := p.parseVersionChange(changeNode, "change")
+	if err != nil {
+		return nil, err
+	}
+	rule, err := newRule(patternNode.Value, change)
+	if err != nil {
+		return nil, p.errorf(patternNode, "%v", err)
+	}
+	return rule, nil
+}
+
+func (p *parser) parseBool(node *yaml.Node, target **bool) error {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+		return p.errorf(node, "expected a boolean")
+	}
+	var b bool
+	err := node.Decode(&b)
+	if err != nil {
+		return p.errorf(node, "expected a boolean")
+	}
+	*target = &b
+	return nil
+}
+
+func (p *parser) parseString(node *yaml.Node, target **string) error {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!str" {
+		return p.errorf(node, "expected a string")
+	}
+	s := node.Value
+	*target = &s
+	return nil
+}
+
+func (p *parser) parseConcurrency(node *yaml.Node) error {
+	var n int
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!int" || node.Decode(&n) != nil {
+		return p.errorf(node, "expected an integer")
+	}
+	if n < 1 {
+		return p.errorf(node, "concurrency must be at least 1")
+	}
+	p.file.Concurrency = &n
+	return nil
+}
+
+// Merge combines files into one File. Values in later files override values in earlier files, so org-level defaults
+// come before repo-level files. Labels are merged with later files overriding the version change of labels with the
+// same name. Label rules from later files are checked before rules from earlier files. Nil files are skipped.
+func Merge(files ...*File) *File {
+	result := &File{}
+	var names []string
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		if f.Filename != "" {
+			names = append(names, f.Filename)
+		}
+		if f.Labels != nil {
+			result.Labels = mergeLabels(result.Labels, f.Labels)
+		}
+		if f.LabelRules != nil {
+			result.LabelRules = append(append([]*conventionalpulls.LabelRule{}, f.LabelRules...), result.LabelRules...)
+		}
+		if f.RequireLabels != nil {
+			result.RequireLabels = f.RequireLabels
+		}
+		if f.StrictLabels != nil {
+			result.StrictLabels = f.StrictLabels
+		}
+		if f.TagPrefix != nil {
+			result.TagPrefix = f.TagPrefix
+		}
+		if f.PreRelease != nil {
+			result.PreRelease = f.PreRelease
+		}
+		if f.InitialDevelopment != nil {
+			result.InitialDevelopment = f.InitialDevelopment
+		}
+		if f.Concurrency != nil {
+			result.Concurrency = f.Concurrency
+		}
+	}
+	result.Filename = strings.Join(names, ", ")
+	return result
+}
+
+// mergeLabels returns a copy of base with overrides added. Label names are compared case-insensitively.
+func mergeLabels(base, overrides map[string]conventionalpulls.VersionChange) map[string]conventionalpulls.VersionChange {
+	result := make(map[string]conventionalpulls.VersionChange, len(base)+len(overrides))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range overrides {
+		for existing := range result {
+			if strings.EqualFold(existing, k) {
+				delete(result, existing)
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// Apply sets the values from f on cfg. Values that aren't set in f are left unchanged.
+func (f *File) Apply(cfg *conventionalpulls.Config) {
+	if f.Labels != nil {
+		cfg.LabelValues = make(map[string]conventionalpulls.VersionChange, len(f.Labels))
+		for k, v := range f.Labels {
+			cfg.LabelValues[k] = v
+		}
+	}
+	if f.LabelRules != nil {
+		cfg.LabelRules = append([]*conventionalpulls.LabelRule{}, f.LabelRules...)
+	}
+	if f.RequireLabels != nil {
+		cfg.RequireLabels = *f.RequireLabels
+	}
+	if f.StrictLabels != nil {
+		cfg.StrictLabels = *f.StrictLabels
+	}
+	if f.PreRelease != nil {
+		cfg.PreRelease = *f.PreRelease
+	}
+	if f.InitialDevelopment != nil {
+		cfg.InitialDevelopment = *f.InitialDevelopment
+	}
+	if f.Concurrency != nil {
+		cfg.Concurrency = *f.Concurrency
+	}
+}
+
+// ApplyTagResolver sets the values from f on r. Values that aren't set in f are left unchanged.
+func (f *File) ApplyTagResolver(r *conventionalpulls.TagResolver) {
+	if f.TagPrefix != nil {
+		r.Prefix = *f.TagPrefix
+	}
+}
+
+// ValidationErr is returned when a config file is invalid
+type ValidationErr struct {
+	Filename string
+
+	// Line and Column are the position of the problem. They are 0 when the position is unknown.
+	Line   int
+	Column int
+
+	Msg string
+}
+
+func (e *ValidationErr) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Filename, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.Filename, e.Line, e.Column, e.Msg)
+}
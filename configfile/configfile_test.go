@@ -0,0 +1,328 @@
+package configfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestParse(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		got, err := Parse(".conventionalpulls.yml", []byte(`
+labels:
+  breaking: major
+  enhancement: Minor
+  bug: patch
+  documentation: none
+require_labels: true
+strict_labels: true
+tag_prefix: v
+pre_release: rc
+initial_development: false
+concurrency: 4
+`))
+		require.NoError(t, err)
+		require.Equal(t, &File{
+			Filename: ".conventionalpulls.yml",
+			Labels: map[string]conventionalpulls.VersionChange{
+				"breaking":      conventionalpulls.VersionChangeMajor,
+				"enhancement":   conventionalpulls.VersionChangeMinor,
+				"bug":           conventionalpulls.VersionChangePatch,
+				"documentation": conventionalpulls.VersionChangeNone,
+			},
+			RequireLabels:      boolPtr(true),
+			StrictLabels:       boolPtr(true),
+			TagPrefix:          stringPtr("v"),
+			PreRelease:         stringPtr("rc"),
+			InitialDevelopment: boolPtr(false),
+			Concurrency:        intPtr(4),
+		}, got)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		got, err := Parse("config.json", []byte(`{
+  "labels": {"bug": "patch"},
+  "require_labels": false
+}`))
+		require.NoError(t, err)
+		require.Equal(t, &File{
+			Filename: "config.json",
+			Labels: map[string]conventionalpulls.VersionChange{
+				"bug": conventionalpulls.VersionChangePatch,
+			},
+			RequireLabels: boolPtr(false),
+		}, got)
+	})
+
+	t.Run("label_rules", func(t *testing.T) {
+		got, err := Parse("c.yml", []byte(`
+label_rules:
+  - glob: "semver[:/]major"
+    change: major
+  - regexp: '^type:\s*feature$'
+    change: Minor
+`))
+		require.NoError(t, err)
+		require.Len(t, got.LabelRules, 2)
+		require.Equal(t, "semver[:/]major", got.LabelRules[0].Pattern())
+		require.Equal(t, conventionalpulls.VersionChangeMajor, got.LabelRules[0].VersionChange())
+		require.True(t, got.LabelRules[0].Match("semver/major"))
+		require.Equal(t, conventionalpulls.VersionChangeMinor, got.LabelRules[1].VersionChange())
+		require.True(t, got.LabelRules[1].Match("type: feature"))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		got, err := Parse("empty.yml", []byte{})
+		require.NoError(t, err)
+		require.Equal(t, &File{Filename: "empty.yml"}, got)
+	})
+
+	for _, td := range []struct {
+		name     string
+		filename string
+		data     string
+		err      string
+	}{
+		{
+			name: "not a mapping",
+			data: "- foo\n",
+			err:  "c.yml:1:1: config must be a mapping",
+		},
+		{
+			name: "unknown key",
+			data: "require_labels: true\nrequire_label: true\n",
+			err:  `c.yml:2:1: unknown key "require_label"`,
+		},
+		{
+			name: "duplicate key",
+			data: "tag_prefix: v\ntag_prefix: x\n",
+			err:  `c.yml:2:1: duplicate key "tag_prefix"`,
+		},
+		{
+			name: "invalid version change",
+			data: "labels:\n  bug: patch\n  feature: big\n",
+			err:  `c.yml:3:12: version change for label "feature" must be one of none, patch, minor or major`,
+		},
+		{
+			name: "duplicate label",
+			data: "labels:\n  bug: patch\n  Bug: minor\n",
+			err:  `c.yml:3:3: duplicate label "Bug"`,
+		},
+		{
+			name: "labels not a mapping",
+			data: "labels: [bug]\n",
+			err:  "c.yml:1:9: labels must be a mapping of label names to version changes",
+		},
+		{
+			name: "label_rules not a list",
+			data: "label_rules:\n  glob: x\n",
+			err:  "c.yml:2:3: label_rules must be a list",
+		},
+		{
+			name: "label rule without pattern",
+			data: "label_rules:\n  - change: major\n",
+			err:  "c.yml:2:5: label rule must have a glob or regexp",
+		},
+		{
+			name: "label rule without change",
+			data: "label_rules:\n  - glob: x\n",
+			err:  "c.yml:2:5: label rule must have a change",
+		},
+		{
+			name: "label rule with two patterns",
+			data: "label_rules:\n  - glob: x\n    regexp: x\n    change: major\n",
+			err:  "c.yml:3:5: label rule must have only one of glob or regexp",
+		},
+		{
+			name: "label rule unknown key",
+			data: "label_rules:\n  - glob: x\n    version_change: major\n",
+			err:  `c.yml:3:5: unknown key "version_change"`,
+		},
+		{
+			name: "label rule invalid change",
+			data: "label_rules:\n  - glob: x\n    change: huge\n",
+			err:  "c.yml:3:13: change must be one of none, patch, minor or major",
+		},
+		{
+			name: "label rule invalid glob",
+			data: "label_rules:\n  - glob: 'semver:[major'\n    change: major\n",
+			err:  `c.yml:2:11: invalid label pattern "semver:[major": unterminated character class`,
+		},
+		{
+			name: "label rule invalid regexp",
+			data: "label_rules:\n  - regexp: 'semver(major'\n    change: major\n",
+			err:  "c.yml:2:13: invalid label pattern \"semver(major\": error parsing regexp: missing closing ): `(?i)semver(major`",
+		},
+		{
+			name: "not a bool",
+			data: "require_labels: yes please\n",
+			err:  "c.yml:1:17: expected a boolean",
+		},
+		{
+			name: "not a string",
+			data: "tag_prefix: 1\n",
+			err:  "c.yml:1:13: expected a string",
+		},
+		{
+			name: "invalid pre_release",
+			data: "pre_release: rc.1\n",
+			err:  `c.yml:1:14: invalid pre_release "rc.1"`,
+		},
+		{
+			name: "not an int",
+			data: "concurrency: lots\n",
+			err:  "c.yml:1:14: expected an integer",
+		},
+		{
+			name: "concurrency too low",
+			data: "concurrency: 0\n",
+			err:  "c.yml:1:14: concurrency must be at least 1",
+		},
+		{
+			name: "yaml syntax",
+			data: "labels: [\n",
+			err:  "c.yml: line 1: did not find expected node content",
+		},
+		{
+			name:     "json syntax",
+			filename: "c.json",
+			data:     "{\n  \"tag_prefix\": \"v\",\n}",
+			err:      "c.json:3:2: invalid character '}' looking for beginning of object key string",
+		},
+		{
+			name:     "json position",
+			filename: "c.json",
+			data:     "{\n  \"tag_prefix\": true\n}",
+			err:      "c.json:2:17: expected a string",
+		},
+	} {
+		td := td
+		t.Run(td.name, func(t *testing.T) {
+			filename := td.filename
+			if filename == "" {
+				filename = "c.yml"
+			}
+			got, err := Parse(filename, []byte(td.data))
+			require.EqualError(t, err, td.err)
+			require.IsType(t, &ValidationErr{}, err)
+			require.Nil(t, got)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+	filename := filepath.Join(dir, DefaultFilename)
+	err = ioutil.WriteFile(filename, []byte("tag_prefix: foo/v\n"), 0o600)
+	require.NoError(t, err)
+	got, err := Load(filename)
+	require.NoError(t, err)
+	require.Equal(t, &File{Filename: filename, TagPrefix: stringPtr("foo/v")}, got)
+
+	_, err = Load(filepath.Join(dir, "missing.yml"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestMerge(t *testing.T) {
+	orgRule, err := conventionalpulls.GlobLabelRule("semver:*", conventionalpulls.VersionChangePatch)
+	require.NoError(t, err)
+	repoRule, err := conventionalpulls.GlobLabelRule("semver:major", conventionalpulls.VersionChangeMajor)
+	require.NoError(t, err)
+	org := &File{
+		Filename: "org.yml",
+		Labels: map[string]conventionalpulls.VersionChange{
+			"bug":         conventionalpulls.VersionChangePatch,
+			"enhancement": conventionalpulls.VersionChangeMinor,
+		},
+		LabelRules:    []*conventionalpulls.LabelRule{orgRule},
+		RequireLabels: boolPtr(true),
+		TagPrefix:     stringPtr("v"),
+		Concurrency:   intPtr(2),
+	}
+	repo := &File{
+		Filename: "repo.yml",
+		Labels: map[string]conventionalpulls.VersionChange{
+			"Enhancement": conventionalpulls.VersionChangePatch,
+			"breaking":    conventionalpulls.VersionChangeMajor,
+		},
+		LabelRules:    []*conventionalpulls.LabelRule{repoRule},
+		RequireLabels: boolPtr(false),
+		StrictLabels:  boolPtr(true),
+		PreRelease:    stringPtr("beta"),
+	}
+	got := Merge(org, nil, repo)
+	require.Equal(t, &File{
+		Filename: "org.yml, repo.yml",
+		Labels: map[string]conventionalpulls.VersionChange{
+			"bug":         conventionalpulls.VersionChangePatch,
+			"Enhancement": conventionalpulls.VersionChangePatch,
+			"breaking":    conventionalpulls.VersionChangeMajor,
+		},
+		LabelRules:    []*conventionalpulls.LabelRule{repoRule, orgRule},
+		RequireLabels: boolPtr(false),
+		StrictLabels:  boolPtr(true),
+		TagPrefix:     stringPtr("v"),
+		PreRelease:    stringPtr("beta"),
+		Concurrency:   intPtr(2),
+	}, got)
+	require.Len(t, org.Labels, 2)
+	require.Len(t, org.LabelRules, 1)
+
+	require.Equal(t, &File{}, Merge())
+}
+
+func TestFile_Apply(t *testing.T) {
+	rule, err := conventionalpulls.GlobLabelRule("semver:*", conventionalpulls.VersionChangeMinor)
+	require.NoError(t, err)
+	cfg := &conventionalpulls.Config{
+		RequireLabels: true,
+		PreRelease:    "rc",
+		Concurrency:   3,
+	}
+	file := &File{
+		Labels: map[string]conventionalpulls.VersionChange{
+			"bug": conventionalpulls.VersionChangePatch,
+		},
+		LabelRules:         []*conventionalpulls.LabelRule{rule},
+		InitialDevelopment: boolPtr(true),
+		PreRelease:         stringPtr(""),
+	}
+	file.Apply(cfg)
+	require.Equal(t, &conventionalpulls.Config{
+		LabelValues: map[string]conventionalpulls.VersionChange{
+			"bug": conventionalpulls.VersionChangePatch,
+		},
+		LabelRules:         []*conventionalpulls.LabelRule{rule},
+		RequireLabels:      true,
+		InitialDevelopment: true,
+		Concurrency:        3,
+	}, cfg)
+	require.Equal(t, conventionalpulls.VersionChangePatch, cfg.LabelsVersionChange([]string{"Bug"}))
+	require.Equal(t, conventionalpulls.VersionChangeMinor, cfg.LabelsVersionChange([]string{"semver:minor"}))
+
+	resolver := &conventionalpulls.TagResolver{Prefix: "v"}
+	(&File{}).ApplyTagResolver(resolver)
+	require.Equal(t, "v", resolver.Prefix)
+	(&File{TagPrefix: stringPtr("mod/v")}).ApplyTagResolver(resolver)
+	require.Equal(t, "mod/v", resolver.Prefix)
+}
@@ -0,0 +1,143 @@
+package conventionalcommits
+
+import (
+	"context"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+// ClassifyMessage returns the version change for a commit message. A breaking change ("!" in the header or a
+// "BREAKING CHANGE" footer) is a major change whatever the message's type. Otherwise, the change is the value
+// configured for the message's type. Returns false when message isn't a Conventional Commits message or it isn't
+// breaking and its type isn't configured.
+func (c *Classifier) ClassifyMessage(message string) (conventionalpulls.VersionChange, bool) {
+	report := c.commitReport(conventionalpulls.Commit{Message: message})
+	return report.VersionChange, report.Conventional
+}
+
+func (c *Classifier) commitReport(commit conventionalpulls.Commit) *conventionalpulls.CommitReport {
+	report := &conventionalpulls.CommitReport{
+		SHA:     commit.SHA,
+		Subject: commit.Subject(),
+	}
+	msg, err := ParseMessage(commit.Message)
+	if err != nil {
+		return report
+	}
+	report.Type = msg.Type
+	report.Scope = msg.Scope
+	report.Breaking = msg.BreakingChange()
+	if report.Breaking {
+		report.Conventional = true
+		report.VersionChange = conventionalpulls.VersionChangeMajor
+		return report
+	}
+	report.VersionChange, report.Conventional = c.typeChange(msg.Type)
+	return report
+}
+
+// CommitAnalyzer finds the version change for the commits in a git range from their Conventional Commits messages.
+// It is for repositories that take direct pushes instead of pull requests.
+type CommitAnalyzer struct {
+	CommitLister conventionalpulls.CommitLister
+
+	// Classifier sets the change for each commit type. A nil Classifier uses DefaultTypes.
+	Classifier *Classifier
+
+	// RequireConventional returns a *NonConventionalCommitErr when a commit message isn't a Conventional Commits
+	// message with a configured type. Otherwise, those commits are no change.
+	RequireConventional bool
+}
+
+// VersionChange returns the change for a release of the commits reachable from headRef but not baseRef
+func (a *CommitAnalyzer) VersionChange(ctx context.Context, baseRef, headRef string) (conventionalpulls.VersionChange, error) {
+	report, err := a.Explain(ctx, nil, "", baseRef, headRef)
+	if err != nil {
+		return conventionalpulls.VersionChangeNone, err
+	}
+	return report.VersionChange, nil
+}
+
+// NextVersion returns the next version for a release of the commits reachable from headRef but not baseRef. cfg's
+// PreRelease and InitialDevelopment are applied like they are for pull requests. A nil cfg is an empty Config.
+func (a *CommitAnalyzer) NextVersion(ctx context.Context, cfg *conventionalpulls.Config, prevVersion, baseRef, headRef string) (string, error) {
+	report, err := a.Explain(ctx, cfg, prevVersion, baseRef, headRef)
+	if err != nil {
+		return "", err
+	}
+	return report.NextVersion, nil
+}
+
+// Explain returns a Report for a release of the commits reachable from headRef but not baseRef. When prevVersion is
+// empty, the report has no previous or next version. A nil cfg is an empty Config.
+func (a *CommitAnalyzer) Explain(ctx context.Context, cfg *conventionalpulls.Config, prevVersion, baseRef, headRef string) (*conventionalpulls.Report, error) {
+	if a.CommitLister == nil {
+		panic("CommitLister shant be nil")
+	}
+	if cfg == nil {
+		cfg = &conventionalpulls.Config{}
+	}
+	commits, err := a.CommitLister.ListCommits(ctx, baseRef, headRef)
+	if err != nil {
+		return nil, &CommitListerErr{err: err}
+	}
+	report := &conventionalpulls.Report{
+		PreviousVersion: prevVersion,
+		DrivingPRs:      []int{},
+		PullRequests:    []*conventionalpulls.PRReport{},
+		DrivingCommits:  []string{},
+		Commits:         make([]*conventionalpulls.CommitReport, 0, len(commits)),
+	}
+	var nonConventional []string
+	for _, commit := range commits {
+		commitReport := a.Classifier.commitReport(commit)
+		if !commitReport.Conventional {
+			nonConventional = append(nonConventional, commit.SHA)
+		}
+		if commitReport.VersionChange > report.VersionChange {
+			report.VersionChange = commitReport.VersionChange
+		}
+		report.Commits = append(report.Commits, commitReport)
+	}
+	if a.RequireConventional && len(nonConventional) > 0 {
+		return nil, &NonConventionalCommitErr{SHAs: nonConventional}
+	}
+	if report.VersionChange != conventionalpulls.VersionChangeNone {
+		for _, commitReport := range report.Commits {
+			if commitReport.VersionChange == report.VersionChange {
+				report.DrivingCommits = append(report.DrivingCommits, commitReport.SHA)
+			}
+		}
+	}
+	if prevVersion != "" {
+		report.NextVersion, err = cfg.IncrementVersion(prevVersion, report.VersionChange)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// NonConventionalCommitErr is an error indicating that one or more commit messages aren't Conventional Commits
+// messages with a configured type
+type NonConventionalCommitErr struct {
+	SHAs []string
+}
+
+func (e *NonConventionalCommitErr) Error() string {
+	return "one or more commits are not conventional commits"
+}
+
+// CommitListerErr is an error indicating a problem listing commits
+type CommitListerErr struct {
+	err error
+}
+
+// Unwrap meets xerrors.Wrapper
+func (e *CommitListerErr) Unwrap() error {
+	return e.err
+}
+
+func (e *CommitListerErr) Error() string {
+	return "error from CommitLister"
+}
@@ -0,0 +1,164 @@
+package conventionalcommits
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+)
+
+type commitListerFunc func(ctx context.Context, baseRef, headRef string) ([]conventionalpulls.Commit, error)
+
+func (f commitListerFunc) ListCommits(ctx context.Context, baseRef, headRef string) ([]conventionalpulls.Commit, error) {
+	return f(ctx, baseRef, headRef)
+}
+
+func staticCommits(commits ...conventionalpulls.Commit) conventionalpulls.CommitLister {
+	return commitListerFunc(func(_ context.Context, baseRef, headRef string) ([]conventionalpulls.Commit, error) {
+		if baseRef != "v1.2.3" || headRef != "main" {
+			return nil, assert.AnError
+		}
+		return commits, nil
+	})
+}
+
+var testCommits = []conventionalpulls.Commit{
+	{SHA: "1111111111", Message: "docs: explain widgets"},
+	{SHA: "2222222222", Message: "feat(api): add widgets\n\nRefs #12"},
+	{SHA: "3333333333", Message: "Update README.md"},
+	{SHA: "4444444444", Message: "fix: stop crashing"},
+	{SHA: "5555555555", Message: "perf: go faster\n\nReviewed-by: Z"},
+	{SHA: "6666666666", Message: "feat: add gadgets"},
+}
+
+func TestClassifier_ClassifyMessage(t *testing.T) {
+	for _, td := range []struct {
+		message string
+		want    conventionalpulls.VersionChange
+		wantOK  bool
+	}{
+		{message: "feat: add widgets", want: conventionalpulls.VersionChangeMinor, wantOK: true},
+		{message: "fix: stop crashing\n\nBREAKING-CHANGE: crashes are gone", want: conventionalpulls.VersionChangeMajor, wantOK: true},
+		{message: "docs: explain\n\nThis is not a BREAKING CHANGE: really", want: conventionalpulls.VersionChangeNone, wantOK: true},
+		{message: "Update README.md", want: conventionalpulls.VersionChangeNone, wantOK: false},
+		{message: "wip: stuff", want: conventionalpulls.VersionChangeNone, wantOK: false},
+		{message: "feature!: drop API", want: conventionalpulls.VersionChangeMajor, wantOK: true},
+	} {
+		got, ok := (&Classifier{}).ClassifyMessage(td.message)
+		require.Equal(t, td.want, got, td.message)
+		require.Equal(t, td.wantOK, ok, td.message)
+	}
+}
+
+func TestCommitAnalyzer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Explain", func(t *testing.T) {
+		analyzer := &CommitAnalyzer{CommitLister: staticCommits(testCommits...)}
+		got, err := analyzer.Explain(ctx, nil, "v1.2.3", "v1.2.3", "main")
+		require.NoError(t, err)
+		require.Equal(t, &conventionalpulls.Report{
+			PreviousVersion: "v1.2.3",
+			NextVersion:     "v1.3.0",
+			VersionChange:   conventionalpulls.VersionChangeMinor,
+			DrivingPRs:      []int{},
+			PullRequests:    []*conventionalpulls.PRReport{},
+			DrivingCommits:  []string{"2222222222", "6666666666"},
+			Commits: []*conventionalpulls.CommitReport{
+				{SHA: "1111111111", Subject: "docs: explain widgets", Conventional: true, Type: "docs", VersionChange: conventionalpulls.VersionChangeNone},
+				{SHA: "2222222222", Subject: "feat(api): add widgets", Conventional: true, Type: "feat", Scope: "api", VersionChange: conventionalpulls.VersionChangeMinor},
+				{SHA: "3333333333", Subject: "Update README.md", VersionChange: conventionalpulls.VersionChangeNone},
+				{SHA: "4444444444", Subject: "fix: stop crashing", Conventional: true, Type: "fix", VersionChange: conventionalpulls.VersionChangePatch},
+				{SHA: "5555555555", Subject: "perf: go faster", Conventional: true, Type: "perf", VersionChange: conventionalpulls.VersionChangePatch},
+				{SHA: "6666666666", Subject: "feat: add gadgets", Conventional: true, Type: "feat", VersionChange: conventionalpulls.VersionChangeMinor},
+			},
+		}, got)
+	})
+
+	t.Run("Explain json", func(t *testing.T) {
+		analyzer := &CommitAnalyzer{CommitLister: staticCommits(testCommits[:2]...)}
+		report, err := analyzer.Explain(ctx, nil, "", "v1.2.3", "main")
+		require.NoError(t, err)
+		got, err := json.Marshal(report)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+  "version_change": "Minor",
+  "driving_prs": [],
+  "pull_requests": [],
+  "driving_commits": ["2222222222"],
+  "commits": [
+    {"sha": "1111111111", "subject": "docs: explain widgets", "conventional": true, "type": "docs", "breaking": false, "version_change": "None"},
+    {"sha": "2222222222", "subject": "feat(api): add widgets", "conventional": true, "type": "feat", "scope": "api", "breaking": false, "version_change": "Minor"}
+  ]
+}`, string(got))
+	})
+
+	t.Run("breaking", func(t *testing.T) {
+		analyzer := &CommitAnalyzer{CommitLister: staticCommits(append(testCommits, conventionalpulls.Commit{
+			SHA:     "7777777777",
+			Message: "refactor: rename widgets\n\nBREAKING CHANGE: widgets are gadgets now",
+		})...)}
+		got, err := analyzer.Explain(ctx, nil, "", "v1.2.3", "main")
+		require.NoError(t, err)
+		require.Equal(t, conventionalpulls.VersionChangeMajor, got.VersionChange)
+		require.Equal(t, []string{"7777777777"}, got.DrivingCommits)
+		require.True(t, got.Commits[6].Breaking)
+		require.Empty(t, got.NextVersion)
+	})
+
+	t.Run("NextVersion with Config", func(t *testing.T) {
+		analyzer := &CommitAnalyzer{CommitLister: staticCommits(testCommits...)}
+		got, err := analyzer.NextVersion(ctx, &conventionalpulls.Config{PreRelease: "rc"}, "v1.2.3", "v1.2.3", "main")
+		require.NoError(t, err)
+		require.Equal(t, "v1.3.0-rc.1", got)
+	})
+
+	t.Run("VersionChange", func(t *testing.T) {
+		analyzer := &CommitAnalyzer{
+			CommitLister: staticCommits(testCommits...),
+			Classifier: &Classifier{Types: map[string]conventionalpulls.VersionChange{
+				"feat": conventionalpulls.VersionChangePatch,
+				"fix":  conventionalpulls.VersionChangePatch,
+			}},
+		}
+		got, err := analyzer.VersionChange(ctx, "v1.2.3", "main")
+		require.NoError(t, err)
+		require.Equal(t, conventionalpulls.VersionChangePatch, got)
+	})
+
+	t.Run("RequireConventional", func(t *testing.T) {
+		analyzer := &CommitAnalyzer{
+			CommitLister:        staticCommits(testCommits...),
+			RequireConventional: true,
+		}
+		got, err := analyzer.Explain(ctx, nil, "v1.2.3", "v1.2.3", "main")
+		require.EqualError(t, err, "one or more commits are not conventional commits")
+		require.Equal(t, &NonConventionalCommitErr{SHAs: []string{"3333333333"}}, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("lister error", func(t *testing.T) {
+		analyzer := &CommitAnalyzer{CommitLister: staticCommits()}
+		got, err := analyzer.VersionChange(ctx, "v1.0.0", "main")
+		require.IsType(t, &CommitListerErr{}, err)
+		require.True(t, errors.Is(err, assert.AnError))
+		require.Equal(t, conventionalpulls.VersionChangeNone, got)
+	})
+
+	t.Run("invalid previous version", func(t *testing.T) {
+		analyzer := &CommitAnalyzer{CommitLister: staticCommits(testCommits...)}
+		_, err := analyzer.NextVersion(ctx, nil, "foo", "v1.2.3", "main")
+		require.Error(t, err)
+	})
+
+	t.Run("nil CommitLister", func(t *testing.T) {
+		require.Panics(t, func() {
+			_, err := (&CommitAnalyzer{}).VersionChange(ctx, "v1.2.3", "main")
+			require.NoError(t, err)
+		})
+	})
+}
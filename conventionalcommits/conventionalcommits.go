@@ -0,0 +1,180 @@
+// Package conventionalcommits classifies pull requests by their Conventional Commits (conventionalcommits.org)
+// titles instead of their labels.
+package conventionalcommits
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+var (
+	headerExp         = regexp.MustCompile(`^([A-Za-z][\w-]*)(?:\(([^()]*)\))?(!)?: +(\S.*)$`)
+	breakingFooterExp = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+)
+
+// DefaultTypes are the version changes for the commit types from the Conventional Commits spec and the Angular
+// convention it is based on.
+var DefaultTypes = map[string]conventionalpulls.VersionChange{
+	"feat":     conventionalpulls.VersionChangeMinor,
+	"fix":      conventionalpulls.VersionChangePatch,
+	"perf":     conventionalpulls.VersionChangePatch,
+	"build":    conventionalpulls.VersionChangeNone,
+	"chore":    conventionalpulls.VersionChangeNone,
+	"ci":       conventionalpulls.VersionChangeNone,
+	"docs":     conventionalpulls.VersionChangeNone,
+	"refactor": conventionalpulls.VersionChangeNone,
+	"revert":   conventionalpulls.VersionChangeNone,
+	"style":    conventionalpulls.VersionChangeNone,
+	"test":     conventionalpulls.VersionChangeNone,
+}
+
+// DefaultLabelNames are the labels that NewPRLabelFetcher adds for each version change. It is
+// conventionalpulls.DefaultLabelNames, the labels that conventionalpulls.Config uses when LabelValues is nil.
+var DefaultLabelNames = conventionalpulls.DefaultLabelNames
+
+// Header is a parsed Conventional Commits header such as "feat(api)!: add widgets"
+type Header struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// ParseHeader parses a Conventional Commits header. Returns an error when header doesn't have the form
+// "type(scope)!: description", where the scope and "!" are optional.
+func ParseHeader(header string) (*Header, error) {
+	match := headerExp.FindStringSubmatch(strings.TrimSpace(header))
+	if match == nil {
+		return nil, fmt.Errorf("%q is not a conventional commit header", header)
+	}
+	return &Header{
+		Type:        match[1],
+		Scope:       match[2],
+		Breaking:    match[3] == "!",
+		Description: match[4],
+	}, nil
+}
+
+// HasBreakingFooter returns true when body has a line starting with "BREAKING CHANGE: " or "BREAKING-CHANGE: "
+func HasBreakingFooter(body string) bool {
+	return breakingFooterExp.MatchString(body)
+}
+
+// Classifier finds the version change for a pull request from its title and body
+type Classifier struct {
+	// Types maps commit types to version changes. Types are matched case-insensitively. Default is DefaultTypes.
+	Types map[string]conventionalpulls.VersionChange
+}
+
+// Classify returns the version change for a pull request. A "!" in the title or a "BREAKING CHANGE: " footer in the
+// body is a major change whatever the title's type. Otherwise, the change is the value configured for the title's
+// type. Returns false when the title isn't a Conventional Commits header or it isn't breaking and its type isn't
+// configured.
+func (c *Classifier) Classify(title, body string) (conventionalpulls.VersionChange, bool) {
+	header, err := ParseHeader(title)
+	if err != nil {
+		return conventionalpulls.VersionChangeNone, false
+	}
+	if header.Breaking || HasBreakingFooter(body) {
+		return conventionalpulls.VersionChangeMajor, true
+	}
+	return c.typeChange(header.Type)
+}
+
+func (c *Classifier) typeChange(commitType string) (conventionalpulls.VersionChange, bool) {
+	types := DefaultTypes
+	if c != nil && c.Types != nil {
+		types = c.Types
+	}
+	for k, v := range types {
+		if strings.EqualFold(k, commitType) {
+			return v, true
+		}
+	}
+	return conventionalpulls.VersionChangeNone, false
+}
+
+type titlePRFetcher struct {
+	prFetcher  conventionalpulls.PRFetcher
+	classifier *Classifier
+	labelNames map[conventionalpulls.VersionChange]string
+}
+
+// NewPRFetcher returns a PRFetcher that adds the label from labelNames for the version change of each pull request's
+// title to the labels from prFetcher. Use it with NewPRLabelFetcher's arguments so release notes are grouped by the
+// same labels that the version is calculated from.
+//
+// A nil classifier is a Classifier with DefaultTypes. A nil labelNames is DefaultLabelNames.
+func NewPRFetcher(
+	prFetcher conventionalpulls.PRFetcher,
+	classifier *Classifier,
+	labelNames map[conventionalpulls.VersionChange]string,
+) conventionalpulls.PRFetcher {
+	if classifier == nil {
+		classifier = &Classifier{}
+	}
+	if labelNames == nil {
+		labelNames = DefaultLabelNames
+	}
+	return &titlePRFetcher{
+		prFetcher:  prFetcher,
+		classifier: classifier,
+		labelNames: labelNames,
+	}
+}
+
+func (f *titlePRFetcher) FetchPR(ctx context.Context, id int) (*conventionalpulls.PullRequest, error) {
+	pull, err := f.prFetcher.FetchPR(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]string, 0, len(pull.Labels)+1)
+	labels = append(labels, pull.Labels...)
+	change, ok := f.classifier.Classify(pull.Title, pull.Body)
+	if ok {
+		label, ok := f.labelNames[change]
+		if !ok {
+			return nil, fmt.Errorf("no label name is configured for %s", change)
+		}
+		labels = append(labels, label)
+	}
+	labeled := *pull
+	labeled.Labels = labels
+	return &labeled, nil
+}
+
+type prLabelFetcher struct {
+	prFetcher conventionalpulls.PRFetcher
+}
+
+// NewPRLabelFetcher returns a PRLabelFetcher that classifies pull requests from prFetcher with classifier. The
+// fetched labels are the pull request's own labels plus the label from labelNames for its version change, so title
+// classification can be used in conventionalpulls.Config in place of (or alongside) labels. No label is added when
+// the title can't be classified.
+//
+// A nil classifier is a Classifier with DefaultTypes. A nil labelNames is DefaultLabelNames.
+func NewPRLabelFetcher(
+	prFetcher conventionalpulls.PRFetcher,
+	classifier *Classifier,
+	labelNames map[conventionalpulls.VersionChange]string,
+) conventionalpulls.PRLabelFetcher {
+	return &prLabelFetcher{
+		prFetcher: NewPRFetcher(prFetcher, classifier, labelNames),
+	}
+}
+
+func (f *prLabelFetcher) FetchPRLabels(id int) ([]string, error) {
+	return f.FetchPRLabelsContext(context.Background(), id)
+}
+
+func (f *prLabelFetcher) FetchPRLabelsContext(ctx context.Context, id int) ([]string, error) {
+	pull, err := f.prFetcher.FetchPR(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return pull.Labels, nil
+}
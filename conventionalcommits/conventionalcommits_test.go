@@ -0,0 +1,192 @@
+package conventionalcommits
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+)
+
+func TestParseHeader(t *testing.T) {
+	for header, want := range map[string]*Header{
+		"feat: add widgets": {
+			Type:        "feat",
+			Description: "add widgets",
+		},
+		"feat(api)!: remove widgets": {
+			Type:        "feat",
+			Scope:       "api",
+			Breaking:    true,
+			Description: "remove widgets",
+		},
+		"fix(widget-ui): don't crash ": {
+			Type:        "fix",
+			Scope:       "widget-ui",
+			Description: "don't crash",
+		},
+		"Chore!:  drop go 1.13": {
+			Type:        "Chore",
+			Breaking:    true,
+			Description: "drop go 1.13",
+		},
+	} {
+		got, err := ParseHeader(header)
+		require.NoError(t, err, header)
+		require.Equal(t, want, got, header)
+	}
+
+	for _, header := range []string{
+		"add widgets",
+		"feat:add widgets",
+		"feat: ",
+		"feat(api: add widgets",
+		"feat (api): add widgets",
+		": add widgets",
+		"1feat: add widgets",
+	} {
+		got, err := ParseHeader(header)
+		require.EqualError(t, err, fmt.Sprintf("%q is not a conventional commit header", header))
+		require.Nil(t, got)
+	}
+}
+
+func TestHasBreakingFooter(t *testing.T) {
+	require.True(t, HasBreakingFooter("did things\n\nBREAKING CHANGE: the config format changed"))
+	require.True(t, HasBreakingFooter("BREAKING-CHANGE: the config format changed"))
+	require.False(t, HasBreakingFooter("This is not a BREAKING CHANGE: really"))
+	require.False(t, HasBreakingFooter("breaking change: lowercase doesn't count"))
+	require.False(t, HasBreakingFooter(""))
+}
+
+func TestClassifier_Classify(t *testing.T) {
+	for _, td := range []struct {
+		title  string
+		body   string
+		want   conventionalpulls.VersionChange
+		wantOK bool
+	}{
+		{title: "feat: add widgets", want: conventionalpulls.VersionChangeMinor, wantOK: true},
+		{title: "Feat(api): add widgets", want: conventionalpulls.VersionChangeMinor, wantOK: true},
+		{title: "fix: stop crashing", want: conventionalpulls.VersionChangePatch, wantOK: true},
+		{title: "perf: go faster", want: conventionalpulls.VersionChangePatch, wantOK: true},
+		{title: "docs: explain widgets", want: conventionalpulls.VersionChangeNone, wantOK: true},
+		{title: "docs!: explain widgets", want: conventionalpulls.VersionChangeMajor, wantOK: true},
+		{title: "fix: stop crashing", body: "BREAKING CHANGE: crashes are gone", want: conventionalpulls.VersionChangeMajor, wantOK: true},
+		{title: "wip: who knows", want: conventionalpulls.VersionChangeNone, wantOK: false},
+		{title: "feature!: drop API", want: conventionalpulls.VersionChangeMajor, wantOK: true},
+		{title: "wip!: who knows", want: conventionalpulls.VersionChangeMajor, wantOK: true},
+		{title: "wip: who knows", body: "BREAKING CHANGE: nobody knows", want: conventionalpulls.VersionChangeMajor, wantOK: true},
+		{title: "Add widgets", body: "BREAKING CHANGE: crashes are gone", want: conventionalpulls.VersionChangeNone, wantOK: false},
+	} {
+		got, ok := (&Classifier{}).Classify(td.title, td.body)
+		require.Equal(t, td.want, got, td.title)
+		require.Equal(t, td.wantOK, ok, td.title)
+	}
+
+	t.Run("custom types", func(t *testing.T) {
+		classifier := &Classifier{
+			Types: map[string]conventionalpulls.VersionChange{
+				"Feature": conventionalpulls.VersionChangeMinor,
+				"deps":    conventionalpulls.VersionChangePatch,
+			},
+		}
+		got, ok := classifier.Classify("feature: add widgets", "")
+		require.True(t, ok)
+		require.Equal(t, conventionalpulls.VersionChangeMinor, got)
+		got, ok = classifier.Classify("deps: update semver", "")
+		require.True(t, ok)
+		require.Equal(t, conventionalpulls.VersionChangePatch, got)
+		_, ok = classifier.Classify("feat: add widgets", "")
+		require.False(t, ok)
+		// breaking changes don't need a configured type
+		got, ok = classifier.Classify("feat!: drop API", "")
+		require.True(t, ok)
+		require.Equal(t, conventionalpulls.VersionChangeMajor, got)
+	})
+}
+
+type fakePRFetcher map[int]*conventionalpulls.PullRequest
+
+func (f fakePRFetcher) FetchPR(_ context.Context, id int) (*conventionalpulls.PullRequest, error) {
+	pull, ok := f[id]
+	if !ok {
+		return nil, assert.AnError
+	}
+	return pull, nil
+}
+
+func TestNewPRLabelFetcher(t *testing.T) {
+	prFetcher := fakePRFetcher{
+		1: {Number: 1, Title: "feat: add widgets", Labels: []string{"area/api"}},
+		2: {Number: 2, Title: "fix!: remove widget crash"},
+		3: {Number: 3, Title: "Add gadgets"},
+		4: {Number: 4, Title: "docs: explain widgets"},
+		5: {Number: 5, Title: "Add gadgets", Labels: []string{"Patch"}},
+	}
+
+	t.Run("FetchPRLabels", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(prFetcher, nil, nil)
+		for id, want := range map[int][]string{
+			1: {"area/api", "Minor Change"},
+			2: {"Breaking Change"},
+			3: {},
+			4: {"Non-Production Change"},
+		} {
+			got, err := fetcher.FetchPRLabels(id)
+			require.NoError(t, err)
+			require.Equal(t, want, got, id)
+		}
+		_, err := fetcher.FetchPRLabels(6)
+		require.Equal(t, assert.AnError, err)
+	})
+
+	t.Run("label names", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(prFetcher, nil, map[conventionalpulls.VersionChange]string{
+			conventionalpulls.VersionChangeMinor: "semver:minor",
+		})
+		got, err := fetcher.FetchPRLabels(1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"area/api", "semver:minor"}, got)
+		_, err = fetcher.FetchPRLabels(2)
+		require.EqualError(t, err, "no label name is configured for Major")
+	})
+
+	t.Run("with Config", func(t *testing.T) {
+		cfg := &conventionalpulls.Config{
+			PRLabelFetcher: NewPRLabelFetcher(prFetcher, nil, nil),
+			RequireLabels:  true,
+		}
+		got, err := cfg.NextVersion("v1.2.3", 1, 4, 5)
+		require.NoError(t, err)
+		require.Equal(t, "v1.3.0", got)
+		got, err = cfg.NextVersion("v1.2.3", 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, "v2.0.0", got)
+		_, err = cfg.NextVersion("v1.2.3", 1, 3)
+		require.Equal(t, &conventionalpulls.PRMissingLabelErr{IDs: []int{3}}, err)
+	})
+}
+
+func TestNewPRFetcher(t *testing.T) {
+	original := &conventionalpulls.PullRequest{Number: 1, Title: "feat: add widgets", Labels: []string{"area/api"}}
+	fetcher := NewPRFetcher(fakePRFetcher{
+		1: original,
+		2: {Number: 2, Title: "Add gadgets"},
+	}, nil, nil)
+	got, err := fetcher.FetchPR(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, &conventionalpulls.PullRequest{
+		Number: 1,
+		Title:  "feat: add widgets",
+		Labels: []string{"area/api", "Minor Change"},
+	}, got)
+	require.Equal(t, []string{"area/api"}, original.Labels)
+	got, err = fetcher.FetchPR(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{}, got.Labels)
+	_, err = fetcher.FetchPR(context.Background(), 3)
+	require.Equal(t, assert.AnError, err)
+}
@@ -0,0 +1,98 @@
+package conventionalcommits
+
+import (
+	"regexp"
+	"strings"
+)
+
+// footerExp matches the start of a footer: a token followed by ": " or " #"
+var footerExp = regexp.MustCompile(`^(BREAKING CHANGE|[\w-]+)(: | #)`)
+
+// Message is a commit message parsed according to the Conventional Commits 1.0 spec
+type Message struct {
+	Header
+	Body    string
+	Footers []Footer
+}
+
+// Footer is a git trailer-style footer such as "Reviewed-by: Z" or "Refs #123"
+type Footer struct {
+	Token string
+	Value string
+}
+
+// BreakingChange returns true when the header has a "!" or there is a "BREAKING CHANGE" or "BREAKING-CHANGE" footer
+func (m *Message) BreakingChange() bool {
+	if m.Breaking {
+		return true
+	}
+	for _, footer := range m.Footers {
+		if footer.Token == "BREAKING CHANGE" || footer.Token == "BREAKING-CHANGE" {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMessage parses a commit message. The first line is the header. The last paragraph is the footers when every
+// line in it is either a footer or a continuation line that starts with whitespace, like git trailers. A footer's
+// value runs until the next footer. Returns an error when the header isn't a Conventional Commits header.
+func ParseMessage(message string) (*Message, error) {
+	message = strings.ReplaceAll(message, "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	header, err := ParseHeader(lines[0])
+	if err != nil {
+		return nil, err
+	}
+	msg := &Message{Header: *header}
+	lines = lines[1:]
+	footerStart := len(lines)
+	for footerStart > 0 && strings.TrimSpace(lines[footerStart-1]) != "" {
+		footerStart--
+	}
+	if !isFooterParagraph(lines[footerStart:]) {
+		footerStart = len(lines)
+	}
+	msg.Body = strings.TrimSpace(strings.Join(lines[:footerStart], "\n"))
+	msg.Footers = parseFooters(lines[footerStart:])
+	return msg, nil
+}
+
+// isFooterParagraph returns true when lines start with a footer and every other line is a footer or a continuation
+func isFooterParagraph(lines []string) bool {
+	if len(lines) == 0 || !footerExp.MatchString(lines[0]) {
+		return false
+	}
+	for _, line := range lines[1:] {
+		if !footerExp.MatchString(line) && !isContinuation(line) {
+			return false
+		}
+	}
+	return true
+}
+
+func isContinuation(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+func parseFooters(lines []string) []Footer {
+	var footers []Footer
+	var value []string
+	flush := func() {
+		if len(footers) > 0 {
+			footers[len(footers)-1].Value = strings.TrimSpace(strings.Join(value, "\n"))
+		}
+	}
+	for _, line := range lines {
+		match := footerExp.FindStringSubmatch(line)
+		if match == nil {
+			value = append(value, strings.TrimSpace(line))
+			continue
+		}
+		flush()
+		footers = append(footers, Footer{Token: match[1]})
+		value = []string{strings.TrimPrefix(line, match[0])}
+	}
+	flush()
+	return footers
+}
@@ -0,0 +1,130 @@
+package conventionalcommits
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMessage(t *testing.T) {
+	for _, td := range []struct {
+		name         string
+		message      string
+		want         *Message
+		wantBreaking bool
+	}{
+		{
+			name:    "header only",
+			message: "feat: add widgets\n",
+			want: &Message{
+				Header: Header{Type: "feat", Description: "add widgets"},
+			},
+		},
+		{
+			name:    "body",
+			message: "fix(api): stop crashing\n\nThe api crashed.\n\nNow it doesn't.",
+			want: &Message{
+				Header: Header{Type: "fix", Scope: "api", Description: "stop crashing"},
+				Body:   "The api crashed.\n\nNow it doesn't.",
+			},
+		},
+		{
+			name: "footers",
+			message: "fix: prevent racing of requests\n\n" +
+				"Introduce a request id and a reference to latest request. Dismiss\n" +
+				"incoming responses other than from latest request.\n\n" +
+				"Remove timeouts which were used to mitigate the racing issue but are\n" +
+				"obsolete now.\n\n" +
+				"Reviewed-by: Z\n" +
+				"Refs #123\n",
+			want: &Message{
+				Header: Header{Type: "fix", Description: "prevent racing of requests"},
+				Body: "Introduce a request id and a reference to latest request. Dismiss\n" +
+					"incoming responses other than from latest request.\n\n" +
+					"Remove timeouts which were used to mitigate the racing issue but are\n" +
+					"obsolete now.",
+				Footers: []Footer{
+					{Token: "Reviewed-by", Value: "Z"},
+					{Token: "Refs", Value: "123"},
+				},
+			},
+		},
+		{
+			name: "breaking change footer with continuation lines",
+			message: "feat: allow provided config object to extend other configs\n\n" +
+				"BREAKING CHANGE: `extends` key in config file is now used for extending other config files.\n" +
+				"  Configs that used `extends` for something else need to rename it.\n" +
+				"Reviewed-by: Z",
+			want: &Message{
+				Header: Header{Type: "feat", Description: "allow provided config object to extend other configs"},
+				Footers: []Footer{
+					{
+						Token: "BREAKING CHANGE",
+						Value: "`extends` key in config file is now used for extending other config files.\n" +
+							"Configs that used `extends` for something else need to rename it.",
+					},
+					{Token: "Reviewed-by", Value: "Z"},
+				},
+			},
+			wantBreaking: true,
+		},
+		{
+			name:    "footer-like paragraph before the last paragraph",
+			message: "fix: stop crashing\n\nNote: this is prose.\n\nReviewed-by: Z",
+			want: &Message{
+				Header:  Header{Type: "fix", Description: "stop crashing"},
+				Body:    "Note: this is prose.",
+				Footers: []Footer{{Token: "Reviewed-by", Value: "Z"}},
+			},
+		},
+		{
+			name:    "last paragraph with prose lines",
+			message: "fix: stop crashing\n\nNote: this is prose.\nBREAKING CHANGE: is only mentioned here.\nSo is Refs #123.",
+			want: &Message{
+				Header: Header{Type: "fix", Description: "stop crashing"},
+				Body:   "Note: this is prose.\nBREAKING CHANGE: is only mentioned here.\nSo is Refs #123.",
+			},
+		},
+		{
+			name:    "BREAKING-CHANGE token",
+			message: "chore: drop support for Node 6\r\n\r\nBREAKING-CHANGE: use JavaScript features not available in Node 6.\r\n",
+			want: &Message{
+				Header: Header{Type: "chore", Description: "drop support for Node 6"},
+				Footers: []Footer{
+					{Token: "BREAKING-CHANGE", Value: "use JavaScript features not available in Node 6."},
+				},
+			},
+			wantBreaking: true,
+		},
+		{
+			name:    "breaking header",
+			message: "refactor!: drop support for Node 6",
+			want: &Message{
+				Header: Header{Type: "refactor", Breaking: true, Description: "drop support for Node 6"},
+			},
+			wantBreaking: true,
+		},
+		{
+			name:    "footer-like line in body paragraph",
+			message: "docs: explain\n\nSee the notes below.\nnote: this is body text\n\nbreaking change: lowercase is not a token",
+			want: &Message{
+				Header: Header{Type: "docs", Description: "explain"},
+				Body:   "See the notes below.\nnote: this is body text\n\nbreaking change: lowercase is not a token",
+			},
+		},
+	} {
+		td := td
+		t.Run(td.name, func(t *testing.T) {
+			got, err := ParseMessage(td.message)
+			require.NoError(t, err)
+			require.Equal(t, td.want, got)
+			require.Equal(t, td.wantBreaking, got.BreakingChange())
+		})
+	}
+
+	t.Run("not conventional", func(t *testing.T) {
+		got, err := ParseMessage("Add widgets\n\nBREAKING CHANGE: widgets")
+		require.Error(t, err)
+		require.Nil(t, got)
+	})
+}
@@ -1,20 +1,24 @@
 package conventionalpulls
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 )
 
 //go:generate mockgen -source $GOFILE -destination internal/mocks/mock_$GOFILE -package mocks
 
-var defaultLabelValues = map[string]VersionChange{
-	"Non-Production Change": VersionChangeNone,
-	"Patch":                 VersionChangePatch,
-	"Minor Change":          VersionChangeMinor,
-	"Breaking Change":       VersionChangeMajor,
+// DefaultLabelNames are the labels Config uses for each version change when LabelValues is nil
+var DefaultLabelNames = map[VersionChange]string{
+	VersionChangeNone:  "Non-Production Change",
+	VersionChangePatch: "Patch",
+	VersionChangeMinor: "Minor Change",
+	VersionChangeMajor: "Breaking Change",
 }
 
 // VersionChange represents the amount to increment a semver
@@ -54,6 +58,34 @@ func (vc VersionChange) String() string {
 	return versionChangeNames[vc]
 }
 
+// MarshalText meets encoding.TextMarshaler
+func (vc VersionChange) MarshalText() ([]byte, error) {
+	if !vc.valid() {
+		return nil, fmt.Errorf("%d is not a valid VersionChange", vc)
+	}
+	return []byte(vc.String()), nil
+}
+
+// UnmarshalText meets encoding.TextUnmarshaler
+func (vc *VersionChange) UnmarshalText(text []byte) error {
+	change, err := ParseVersionChange(string(text))
+	if err != nil {
+		return err
+	}
+	*vc = change
+	return nil
+}
+
+// ParseVersionChange returns the VersionChange named s. Names are matched case-insensitively.
+func ParseVersionChange(s string) (VersionChange, error) {
+	for vc := VersionChangeNone; vc < versionChangeInvalid; vc++ {
+		if strings.EqualFold(s, versionChangeNames[vc]) {
+			return vc, nil
+		}
+	}
+	return versionChangeInvalid, fmt.Errorf("invalid version change %q", s)
+}
+
 // greater returns whichever is higher, ch or other. Panics if either value is invalid.
 func (vc VersionChange) greater(other VersionChange) VersionChange {
 	vc.mustBeValid()
@@ -69,39 +101,198 @@ type PRLabelFetcher interface {
 	FetchPRLabels(id int) (labels []string, err error)
 }
 
+// ContextPRLabelFetcher fetches PR labels with a context for cancellation and timeouts. When Config.PRLabelFetcher
+// also implements ContextPRLabelFetcher, FetchPRLabelsContext is used in place of FetchPRLabels.
+type ContextPRLabelFetcher interface {
+	FetchPRLabelsContext(ctx context.Context, id int) (labels []string, err error)
+}
+
+// ContextFetcher returns a ContextPRLabelFetcher for fetcher. If fetcher already implements ContextPRLabelFetcher,
+// it is returned as-is. Otherwise ctx is checked before each call to fetcher.FetchPRLabels.
+func ContextFetcher(fetcher PRLabelFetcher) ContextPRLabelFetcher {
+	if ctxFetcher, ok := fetcher.(ContextPRLabelFetcher); ok {
+		return ctxFetcher
+	}
+	return &contextFetcher{fetcher: fetcher}
+}
+
+type contextFetcher struct {
+	fetcher PRLabelFetcher
+}
+
+func (f *contextFetcher) FetchPRLabelsContext(ctx context.Context, id int) ([]string, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+	return f.fetcher.FetchPRLabels(id)
+}
+
+// FromContextFetcher returns a PRLabelFetcher for fetcher so it can be used as Config.PRLabelFetcher. The returned
+// fetcher implements both interfaces, and its FetchPRLabels uses context.Background().
+func FromContextFetcher(fetcher ContextPRLabelFetcher) PRLabelFetcher {
+	return &backgroundFetcher{ContextPRLabelFetcher: fetcher}
+}
+
+type backgroundFetcher struct {
+	ContextPRLabelFetcher
+}
+
+func (f *backgroundFetcher) FetchPRLabels(id int) ([]string, error) {
+	return f.FetchPRLabelsContext(context.Background(), id)
+}
+
+// BatchPRLabelFetcher fetches labels for many PRs in one call. When Config.PRLabelFetcher also implements
+// BatchPRLabelFetcher, FetchPRLabelsBatch is used in place of fetching labels one PR at a time. The result must
+// have an entry for every id.
+type BatchPRLabelFetcher interface {
+	FetchPRLabelsBatch(ctx context.Context, ids []int) (labels map[int][]string, err error)
+}
+
+// PRDiscoverer finds the pull requests that were merged between two git refs
+type PRDiscoverer interface {
+	DiscoverPRs(ctx context.Context, baseRef, headRef string) (prIDs []int, err error)
+}
+
 // Config configuration values
 type Config struct {
-	LabelValues    map[string]VersionChange
+	LabelValues map[string]VersionChange
+
+	// LabelRules map labels matching a pattern to a VersionChange. Labels with an exact match in LabelValues (or the
+	// default label values when LabelValues is nil) use that value. Otherwise, the first matching rule applies.
+	LabelRules []*LabelRule
+
 	RequireLabels  bool
 	PRLabelFetcher PRLabelFetcher
+
+	// StrictLabels returns a *PRConflictingLabelErr when a pull request has labels that map to different
+	// VersionChanges instead of using the greatest change.
+	StrictLabels bool
+
+	// PreRelease is a pre-release identifier such as "alpha", "beta" or "rc". When it is set, NextVersion returns
+	// pre-release versions like v1.3.0-rc.1. When it is empty and the previous version is a pre-release, NextVersion
+	// promotes the pre-release to its final version.
+	PreRelease string
+
+	// InitialDevelopment applies the common convention for v0 versions: while the major version is 0, a major change
+	// increments the minor version and a minor change increments the patch version. Use Graduate to release v1.0.0.
+	InitialDevelopment bool
+
+	// PRDiscoverer finds the pull requests to include in NextVersionForRefs
+	PRDiscoverer PRDiscoverer
+
+	// Concurrency is the maximum number of label fetches to run at once. Values less than 1 are treated as 1.
+	Concurrency int
+}
+
+func (cfg *Config) concurrency(jobs int) int {
+	concurrency := cfg.Concurrency
+	if concurrency > jobs {
+		concurrency = jobs
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return concurrency
 }
 
-func (cfg *Config) prLabels(prIDs []int) (map[int][]string, error) {
+// prLabels fetches lowercased labels for prIDs. It uses the PRLabelFetcher's FetchPRLabelsBatch when available.
+func (cfg *Config) prLabels(ctx context.Context, prIDs []int) (map[int][]string, error) {
 	if cfg.PRLabelFetcher == nil {
 		panic("PRLabelFetcher shant be nil")
 	}
+	var fetched map[int][]string
+	var err error
+	if batchFetcher, ok := cfg.PRLabelFetcher.(BatchPRLabelFetcher); ok {
+		fetched, err = batchPRLabels(ctx, batchFetcher, prIDs)
+	} else {
+		fetched, err = cfg.concurrentPRLabels(ctx, prIDs)
+	}
+	if err != nil {
+		return nil, &PRLabelFetcherErr{err: err}
+	}
 	result := make(map[int][]string, len(prIDs))
 	for _, id := range prIDs {
-		labels, err := cfg.PRLabelFetcher.FetchPRLabels(id)
-		if err != nil {
-			return nil, &PRLabelFetcherErr{err: err}
-		}
-		result[id] = make([]string, len(labels))
-		for i, label := range labels {
+		result[id] = make([]string, len(fetched[id]))
+		for i, label := range fetched[id] {
 			result[id][i] = strings.ToLower(label)
 		}
 	}
 	return result, nil
 }
 
+func batchPRLabels(ctx context.Context, fetcher BatchPRLabelFetcher, prIDs []int) (map[int][]string, error) {
+	fetched, err := fetcher.FetchPRLabelsBatch(ctx, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range prIDs {
+		if _, ok := fetched[id]; !ok {
+			return nil, fmt.Errorf("no labels were returned for PR %d", id)
+		}
+	}
+	return fetched, nil
+}
+
+// concurrentPRLabels fetches labels for prIDs with up to cfg.Concurrency fetches at once. The first error cancels any
+// outstanding fetches and is the error returned.
+func (cfg *Config) concurrentPRLabels(ctx context.Context, prIDs []int) (map[int][]string, error) {
+	fetcher := ContextFetcher(cfg.PRLabelFetcher)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	fetched := make([][]string, len(prIDs))
+	var firstErr error
+	var errOnce sync.Once
+	setErr := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.concurrency(len(prIDs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				if ctx.Err() != nil {
+					setErr(ctx.Err())
+					continue
+				}
+				labels, err := fetcher.FetchPRLabelsContext(ctx, prIDs[idx])
+				if err != nil {
+					setErr(err)
+					continue
+				}
+				fetched[idx] = labels
+			}
+		}()
+	}
+	for idx := range prIDs {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	result := make(map[int][]string, len(prIDs))
+	for idx, id := range prIDs {
+		result[id] = fetched[idx]
+	}
+	return result, nil
+}
+
 // PRVersionChange what level of change is required for the given pulls
 func (cfg *Config) PRVersionChange(pullRequestID ...int) (VersionChange, error) {
+	return cfg.PRVersionChangeContext(context.Background(), pullRequestID...)
+}
+
+// PRVersionChangeContext is PRVersionChange with a context that is passed to the PRLabelFetcher
+func (cfg *Config) PRVersionChangeContext(ctx context.Context, pullRequestID ...int) (VersionChange, error) {
 	versionChange := VersionChangeNone
-	prLabels, err := cfg.prLabels(pullRequestID)
-	if err != nil {
-		return 0, err
-	}
-	err = cfg.requireLabels(prLabels)
+	prLabels, err := cfg.checkedPRLabels(ctx, pullRequestID)
 	if err != nil {
 		return 0, err
 	}
@@ -111,31 +302,179 @@ func (cfg *Config) PRVersionChange(pullRequestID ...int) (VersionChange, error)
 	return versionChange, nil
 }
 
+// checkedPRLabels fetches labels for prIDs and checks them against RequireLabels and StrictLabels
+func (cfg *Config) checkedPRLabels(ctx context.Context, prIDs []int) (map[int][]string, error) {
+	prLabels, err := cfg.prLabels(ctx, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	err = cfg.requireLabels(prLabels)
+	if err != nil {
+		return nil, err
+	}
+	err = cfg.strictLabels(prLabels)
+	if err != nil {
+		return nil, err
+	}
+	return prLabels, nil
+}
+
 // NextVersion returns the next version for a release including the given pulls
 func (cfg *Config) NextVersion(prevVersion string, pullRequestID ...int) (string, error) {
-	bump, err := cfg.PRVersionChange(pullRequestID...)
+	return cfg.NextVersionContext(context.Background(), prevVersion, pullRequestID...)
+}
+
+// NextVersionContext is NextVersion with a context that is passed to the PRLabelFetcher
+func (cfg *Config) NextVersionContext(ctx context.Context, prevVersion string, pullRequestID ...int) (string, error) {
+	bump, err := cfg.PRVersionChangeContext(ctx, pullRequestID...)
 	if err != nil {
 		return "", err
 	}
-	return nextVersion(prevVersion, bump)
+	return cfg.nextVersion(prevVersion, bump)
 }
 
-func nextVersion(previousVersion string, bump VersionChange) (string, error) {
+// NextVersionForRefs returns the next version for a release of headRef that includes every pull request merged
+// since baseRef. baseRef is typically the tag for prevVersion.
+func (cfg *Config) NextVersionForRefs(ctx context.Context, prevVersion, baseRef, headRef string) (string, error) {
+	prIDs, err := cfg.discoverPRs(ctx, baseRef, headRef)
+	if err != nil {
+		return "", err
+	}
+	return cfg.NextVersionContext(ctx, prevVersion, prIDs...)
+}
+
+func (cfg *Config) discoverPRs(ctx context.Context, baseRef, headRef string) ([]int, error) {
+	if cfg.PRDiscoverer == nil {
+		panic("PRDiscoverer shant be nil")
+	}
+	prIDs, err := cfg.PRDiscoverer.DiscoverPRs(ctx, baseRef, headRef)
+	if err != nil {
+		return nil, &PRDiscovererErr{err: err}
+	}
+	return prIDs, nil
+}
+
+// Graduate returns v1.0.0 (or a v1.0.0 pre-release when cfg.PreRelease is set) as the version following prevVersion.
+// It is the way to leave v0 when InitialDevelopment is set. Returns an error if prevVersion is already past v0.
+func (cfg *Config) Graduate(prevVersion string) (string, error) {
+	prev, err := semver.NewVersion(prevVersion)
+	if err != nil {
+		return "", fmt.Errorf("could not parse semver from %q", prevVersion)
+	}
+	v1PreRelease := prev.Major() == 1 && prev.Minor() == 0 && prev.Patch() == 0 && prev.Prerelease() != ""
+	if prev.Major() != 0 && !v1PreRelease {
+		return "", fmt.Errorf("%q has already graduated from v0", prevVersion)
+	}
+	return cfg.incrementVersion(prev, VersionChangeMajor)
+}
+
+// IncrementVersion returns the version that follows prevVersion for a release with the given change. It applies
+// PreRelease and InitialDevelopment the same way as NextVersion.
+func (cfg *Config) IncrementVersion(prevVersion string, change VersionChange) (string, error) {
+	if !change.valid() {
+		return "", fmt.Errorf("%d is not a valid VersionChange", change)
+	}
+	return cfg.nextVersion(prevVersion, change)
+}
+
+func (cfg *Config) nextVersion(previousVersion string, bump VersionChange) (string, error) {
 	bump.mustBeValid()
 	prev, err := semver.NewVersion(previousVersion)
 	if err != nil {
 		return "", fmt.Errorf("could not parse semver from %q", previousVersion)
 	}
-	var next semver.Version
+	if cfg.InitialDevelopment && prev.Major() == 0 && bump > VersionChangePatch {
+		bump--
+	}
+	return cfg.incrementVersion(prev, bump)
+}
+
+func (cfg *Config) incrementVersion(prev *semver.Version, bump VersionChange) (string, error) {
+	if prev.Prerelease() == "" {
+		if bump == VersionChangeNone {
+			return prev.Original(), nil
+		}
+		next := incVersion(*prev, bump)
+		if cfg.PreRelease == "" {
+			return next.Original(), nil
+		}
+		return setPreRelease(next, cfg.PreRelease, 1)
+	}
+
+	// prev is a pre-release, so its core version already includes the change implied by its pre-release line.
+	core, err := prev.SetPrerelease("")
+	if err != nil {
+		return "", err
+	}
+	core, err = core.SetMetadata("")
+	if err != nil {
+		return "", err
+	}
+	newLine := bump > preReleaseLineChange(core)
+	if newLine {
+		core = incVersion(core, bump)
+	}
+	if cfg.PreRelease == "" {
+		return core.Original(), nil
+	}
+	if newLine {
+		return setPreRelease(core, cfg.PreRelease, 1)
+	}
+	if bump == VersionChangeNone {
+		return prev.Original(), nil
+	}
+	next, err := setPreRelease(core, cfg.PreRelease, preReleaseNumber(prev.Prerelease(), cfg.PreRelease)+1)
+	if err != nil {
+		return "", err
+	}
+	if semver.MustParse(next).LessThan(prev) {
+		return "", fmt.Errorf("pre-release %q would come before %q", next, prev.Original())
+	}
+	return next, nil
+}
+
+func incVersion(version semver.Version, bump VersionChange) semver.Version {
 	switch bump {
-	case VersionChangeNone:
-		next = *prev
 	case VersionChangePatch:
-		next = prev.IncPatch()
+		return version.IncPatch()
 	case VersionChangeMinor:
-		next = prev.IncMinor()
+		return version.IncMinor()
 	case VersionChangeMajor:
-		next = prev.IncMajor()
+		return version.IncMajor()
+	}
+	return version
+}
+
+// preReleaseLineChange returns the largest change that is already covered by the pre-release line for core.
+// For example v1.3.0-rc.1 is a minor change from v1.2.x, so a minor or patch change stays on the same line.
+func preReleaseLineChange(core semver.Version) VersionChange {
+	switch {
+	case core.Minor() == 0 && core.Patch() == 0:
+		return VersionChangeMajor
+	case core.Patch() == 0:
+		return VersionChangeMinor
+	default:
+		return VersionChangePatch
+	}
+}
+
+// preReleaseNumber returns the numeric suffix of preRelease when it is on the identifier line (e.g. 2 for "rc.2").
+// Returns 0 when preRelease belongs to a different identifier.
+func preReleaseNumber(preRelease, identifier string) int {
+	if !strings.HasPrefix(preRelease, identifier+".") {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(preRelease, identifier+"."))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func setPreRelease(version semver.Version, identifier string, number int) (string, error) {
+	next, err := version.SetPrerelease(fmt.Sprintf("%s.%d", identifier, number))
+	if err != nil {
+		return "", fmt.Errorf("invalid pre-release identifier %q", identifier)
 	}
 	return next.Original(), nil
 }
@@ -162,23 +501,94 @@ func (cfg *Config) requireLabels(prLabels map[int][]string) error {
 	return nil
 }
 
+func (cfg *Config) strictLabels(prLabels map[int][]string) error {
+	if !cfg.StrictLabels {
+		return nil
+	}
+	prIDs := make([]int, 0, len(prLabels))
+	for id := range prLabels {
+		prIDs = append(prIDs, id)
+	}
+	sort.Ints(prIDs)
+	labelValues := cfg.labelValues()
+	var err PRConflictingLabelErr
+	for _, id := range prIDs {
+		var configured []string
+		changes := map[VersionChange]bool{}
+		for _, label := range prLabels[id] {
+			change, ok := cfg.labelVersionChange(labelValues, label)
+			if !ok {
+				continue
+			}
+			configured = append(configured, label)
+			changes[change] = true
+		}
+		if len(changes) < 2 {
+			continue
+		}
+		if err.Labels == nil {
+			err.Labels = map[int][]string{}
+		}
+		err.IDs = append(err.IDs, id)
+		err.Labels[id] = configured
+	}
+	if len(err.IDs) > 0 {
+		return &err
+	}
+	return nil
+}
+
 func (cfg *Config) labelValues() map[string]VersionChange {
-	labels := defaultLabelValues
-	if cfg.LabelValues != nil {
-		labels = cfg.LabelValues
+	if cfg.LabelValues == nil {
+		result := make(map[string]VersionChange, len(DefaultLabelNames))
+		for change, name := range DefaultLabelNames {
+			result[strings.ToLower(name)] = change
+		}
+		return result
 	}
-	result := make(map[string]VersionChange, len(labels))
-	for k, v := range labels {
+	result := make(map[string]VersionChange, len(cfg.LabelValues))
+	for k, v := range cfg.LabelValues {
 		result[strings.ToLower(k)] = v
 	}
 	return result
 }
 
-// containsAnyLabel returns true if any of LabelValues is part of cfg.LabelValues.
+// labelVersionChange returns the change configured for label. Returns false when label has no configured change.
+func (cfg *Config) labelVersionChange(labelValues map[string]VersionChange, label string) (VersionChange, bool) {
+	match, ok := cfg.matchLabel(labelValues, label)
+	return match.VersionChange, ok
+}
+
+// matchLabel returns the configuration that applies to label. An exact match in labelValues takes precedence over
+// cfg.LabelRules, which are checked in order. Returns false when label has no configured change.
+func (cfg *Config) matchLabel(labelValues map[string]VersionChange, label string) (LabelMatch, bool) {
+	change, ok := labelValues[strings.ToLower(label)]
+	if ok {
+		return LabelMatch{
+			Label:         label,
+			RuleType:      "label",
+			Rule:          strings.ToLower(label),
+			VersionChange: change,
+		}, true
+	}
+	for _, rule := range cfg.LabelRules {
+		if rule.Match(label) {
+			return LabelMatch{
+				Label:         label,
+				RuleType:      rule.Type(),
+				Rule:          rule.Pattern(),
+				VersionChange: rule.VersionChange(),
+			}, true
+		}
+	}
+	return LabelMatch{}, false
+}
+
+// containsAnyLabel returns true if any of LabelValues is part of cfg.LabelValues or matches cfg.LabelRules.
 func (cfg *Config) containsAnyLabel(labels []string) bool {
 	labelValues := cfg.labelValues()
 	for _, label := range labels {
-		_, ok := labelValues[strings.ToLower(label)]
+		_, ok := cfg.labelVersionChange(labelValues, label)
 		if ok {
 			return true
 		}
@@ -186,13 +596,19 @@ func (cfg *Config) containsAnyLabel(labels []string) bool {
 	return false
 }
 
+// LabelsVersionChange returns the change for a pull request with the given labels. It is the greatest change
+// configured for any of the labels, or VersionChangeNone when none are configured.
+func (cfg *Config) LabelsVersionChange(labels []string) VersionChange {
+	return cfg.maxVersionChange(labels)
+}
+
 // maxVersionChange returns the maximum version change configured for any of the given LabelValues.
 // Returns VersionChangeNone if none have any configured change.
 func (cfg *Config) maxVersionChange(labels []string) VersionChange {
 	change := VersionChangeNone
 	labelValues := cfg.labelValues()
 	for _, label := range labels {
-		labelChange := labelValues[strings.ToLower(label)]
+		labelChange, _ := cfg.labelVersionChange(labelValues, label)
 		change = labelChange.greater(change)
 	}
 	return change
@@ -207,6 +623,19 @@ func (e *PRMissingLabelErr) Error() string {
 	return "one or more PRs have no configured labels"
 }
 
+// PRConflictingLabelErr is an error indicating that one or more pull requests have labels that map to different
+// VersionChanges. It is only returned when Config.StrictLabels is set.
+type PRConflictingLabelErr struct {
+	IDs []int
+
+	// Labels holds the lowercased configured labels of each pull request in IDs
+	Labels map[int][]string
+}
+
+func (e *PRConflictingLabelErr) Error() string {
+	return "one or more PRs have conflicting labels"
+}
+
 // PRLabelFetcherErr is an error indicating a problem fetching pull request labels.
 type PRLabelFetcherErr struct {
 	err error
@@ -220,3 +649,17 @@ func (e *PRLabelFetcherErr) Unwrap() error {
 func (e *PRLabelFetcherErr) Error() string {
 	return "error from PRLabelFetcher"
 }
+
+// PRDiscovererErr is an error indicating a problem discovering pull requests.
+type PRDiscovererErr struct {
+	err error
+}
+
+// Unwrap meets xerrors.Wrapper
+func (e *PRDiscovererErr) Unwrap() error {
+	return e.err
+}
+
+func (e *PRDiscovererErr) Error() string {
+	return "error from PRDiscoverer"
+}
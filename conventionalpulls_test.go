@@ -1,7 +1,13 @@
 package conventionalpulls
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -28,6 +34,37 @@ func TestVersionChange_String(t *testing.T) {
 	})
 }
 
+func TestParseVersionChange(t *testing.T) {
+	for change := VersionChange(0); change < versionChangeInvalid; change++ {
+		got, err := ParseVersionChange(change.String())
+		require.NoError(t, err)
+		require.Equal(t, change, got)
+	}
+	got, err := ParseVersionChange("minor")
+	require.NoError(t, err)
+	require.Equal(t, VersionChangeMinor, got)
+	_, err = ParseVersionChange("Invalid")
+	require.EqualError(t, err, `invalid version change "Invalid"`)
+	_, err = ParseVersionChange("")
+	require.Error(t, err)
+}
+
+func TestVersionChange_MarshalText(t *testing.T) {
+	got, err := VersionChangeMinor.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "Minor", string(got))
+	_, err = versionChangeInvalid.MarshalText()
+	require.EqualError(t, err, "4 is not a valid VersionChange")
+
+	var change VersionChange
+	err = change.UnmarshalText([]byte("major"))
+	require.NoError(t, err)
+	require.Equal(t, VersionChangeMajor, change)
+	err = change.UnmarshalText([]byte("huge"))
+	require.EqualError(t, err, `invalid version change "huge"`)
+	require.Equal(t, VersionChangeMajor, change)
+}
+
 func TestVersionChange_valid(t *testing.T) {
 	require.True(t, VersionChangeMajor.valid())
 	require.True(t, VersionChangeNone.valid())
@@ -65,7 +102,7 @@ func TestConfig_prLabels(t *testing.T) {
 			2: {"baz", "qux"},
 			3: {},
 		}
-		got, err := cfg.prLabels(ids)
+		got, err := cfg.prLabels(context.Background(), ids)
 		require.NoError(t, err)
 		require.Equal(t, want, got)
 	})
@@ -79,7 +116,7 @@ func TestConfig_prLabels(t *testing.T) {
 		cfg := &Config{
 			PRLabelFetcher: mockFetcher,
 		}
-		got, err := cfg.prLabels([]int{1, 2, 3})
+		got, err := cfg.prLabels(context.Background(), []int{1, 2, 3})
 		require.Error(t, err)
 		require.Equal(t, &PRLabelFetcherErr{
 			err: assert.AnError,
@@ -88,6 +125,188 @@ func TestConfig_prLabels(t *testing.T) {
 	})
 }
 
+func TestConfig_prLabels_concurrency(t *testing.T) {
+	t.Run("ordering", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockContextPRLabelFetcher(ctrl)
+		// every fetch waits until all three are running, so this only passes when they run concurrently
+		var running sync.WaitGroup
+		running.Add(3)
+		for id, delay := range map[int]time.Duration{1: 20 * time.Millisecond, 2: 10 * time.Millisecond, 3: 0} {
+			id, delay := id, delay
+			mockFetcher.EXPECT().FetchPRLabelsContext(gomock.Any(), id).DoAndReturn(func(context.Context, int) ([]string, error) {
+				running.Done()
+				running.Wait()
+				time.Sleep(delay)
+				return []string{fmt.Sprintf("Label %d", id)}, nil
+			})
+		}
+		cfg := &Config{
+			PRLabelFetcher: FromContextFetcher(mockFetcher),
+			Concurrency:    3,
+		}
+		got, err := cfg.prLabels(context.Background(), []int{1, 2, 3})
+		require.NoError(t, err)
+		require.Equal(t, map[int][]string{
+			1: {"label 1"},
+			2: {"label 2"},
+			3: {"label 3"},
+		}, got)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockContextPRLabelFetcher(ctrl)
+		var running, maxRunning int32
+		mockFetcher.EXPECT().FetchPRLabelsContext(gomock.Any(), gomock.Any()).Times(10).DoAndReturn(func(context.Context, int) ([]string, error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				prev := atomic.LoadInt32(&maxRunning)
+				if n <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil, nil
+		})
+		cfg := &Config{
+			PRLabelFetcher: FromContextFetcher(mockFetcher),
+			Concurrency:    2,
+		}
+		got, err := cfg.prLabels(context.Background(), []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+		require.NoError(t, err)
+		require.Len(t, got, 10)
+		require.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+	})
+
+	t.Run("first error cancels outstanding fetches", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockContextPRLabelFetcher(ctrl)
+		var running sync.WaitGroup
+		running.Add(3)
+		waitForCancel := func(ctx context.Context, _ int) ([]string, error) {
+			running.Done()
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		mockFetcher.EXPECT().FetchPRLabelsContext(gomock.Any(), 1).DoAndReturn(waitForCancel)
+		mockFetcher.EXPECT().FetchPRLabelsContext(gomock.Any(), 2).DoAndReturn(func(context.Context, int) ([]string, error) {
+			running.Done()
+			running.Wait()
+			return nil, assert.AnError
+		})
+		mockFetcher.EXPECT().FetchPRLabelsContext(gomock.Any(), 3).DoAndReturn(waitForCancel)
+		// 4 is never fetched because the context is canceled before a worker is available
+		cfg := &Config{
+			PRLabelFetcher: FromContextFetcher(mockFetcher),
+			Concurrency:    3,
+		}
+		got, err := cfg.prLabels(context.Background(), []int{1, 2, 3, 4})
+		require.Equal(t, &PRLabelFetcherErr{err: assert.AnError}, err)
+		require.Nil(t, got)
+	})
+}
+
+func TestConfig_prLabels_batch(t *testing.T) {
+	type batchFetcher struct {
+		*mocks.MockPRLabelFetcher
+		*mocks.MockBatchPRLabelFetcher
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockBatchPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabelsBatch(gomock.Any(), []int{1, 2, 3}).Return(map[int][]string{
+			1: {"foo", "bar"},
+			2: {"Baz", "QUX"},
+			3: nil,
+		}, nil)
+		cfg := &Config{
+			PRLabelFetcher: &batchFetcher{MockBatchPRLabelFetcher: mockFetcher},
+		}
+		got, err := cfg.prLabels(context.Background(), []int{1, 2, 3})
+		require.NoError(t, err)
+		require.Equal(t, map[int][]string{
+			1: {"foo", "bar"},
+			2: {"baz", "qux"},
+			3: {},
+		}, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockBatchPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabelsBatch(gomock.Any(), []int{1, 2}).Return(nil, assert.AnError)
+		cfg := &Config{
+			PRLabelFetcher: &batchFetcher{MockBatchPRLabelFetcher: mockFetcher},
+		}
+		got, err := cfg.prLabels(context.Background(), []int{1, 2})
+		require.Equal(t, &PRLabelFetcherErr{err: assert.AnError}, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("missing id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockBatchPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabelsBatch(gomock.Any(), []int{1, 2}).Return(map[int][]string{
+			1: {"foo"},
+		}, nil)
+		cfg := &Config{
+			PRLabelFetcher: &batchFetcher{MockBatchPRLabelFetcher: mockFetcher},
+		}
+		got, err := cfg.prLabels(context.Background(), []int{1, 2})
+		require.Error(t, err)
+		require.Nil(t, got)
+	})
+}
+
+func TestContextFetcher(t *testing.T) {
+	t.Run("wraps PRLabelFetcher", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"foo"}, nil)
+		got, err := ContextFetcher(mockFetcher).FetchPRLabelsContext(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"foo"}, got)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		got, err := ContextFetcher(mockFetcher).FetchPRLabelsContext(ctx, 1)
+		require.Equal(t, context.Canceled, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("already a ContextPRLabelFetcher", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		fetcher := FromContextFetcher(mocks.NewMockContextPRLabelFetcher(ctrl))
+		require.Equal(t, fetcher, ContextFetcher(fetcher))
+	})
+}
+
+func TestFromContextFetcher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockFetcher := mocks.NewMockContextPRLabelFetcher(ctrl)
+	mockFetcher.EXPECT().FetchPRLabelsContext(context.Background(), 1).Return([]string{"foo"}, nil)
+	got, err := FromContextFetcher(mockFetcher).FetchPRLabels(1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, got)
+}
+
 func Test_clConfig_containsAnyLabel(t *testing.T) {
 	t.Run("nil cfg.LabelValues", func(t *testing.T) {
 		cfg := new(Config)
@@ -140,6 +359,24 @@ func Test_clConfig_maxVersionChange(t *testing.T) {
 	})
 }
 
+func TestConfig_LabelsVersionChange(t *testing.T) {
+	cfg := new(Config)
+	require.Equal(t, VersionChangeMajor, cfg.LabelsVersionChange([]string{"Patch", "Breaking Change"}))
+	require.Equal(t, VersionChangePatch, cfg.LabelsVersionChange([]string{"foo", "PATCH"}))
+	require.Equal(t, VersionChangeNone, cfg.LabelsVersionChange(nil))
+}
+
+func TestDefaultLabelNames(t *testing.T) {
+	minor := DefaultLabelNames[VersionChangeMinor]
+	t.Cleanup(func() {
+		DefaultLabelNames[VersionChangeMinor] = minor
+	})
+	DefaultLabelNames[VersionChangeMinor] = "enhancement"
+	cfg := new(Config)
+	require.Equal(t, VersionChangeMinor, cfg.LabelsVersionChange([]string{"Enhancement"}))
+	require.Equal(t, VersionChangeNone, cfg.LabelsVersionChange([]string{minor}))
+}
+
 func TestPRLabelFetcherErr(t *testing.T) {
 	err := &PRLabelFetcherErr{
 		err: assert.AnError,
@@ -202,6 +439,62 @@ func TestConfig_PRVersionChange(t *testing.T) {
 		require.Equal(t, VersionChangeNone, got)
 	})
 
+	t.Run("conflicting labels", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"Non-Production Change", "foo", "Breaking Change"}, nil)
+		mockFetcher.EXPECT().FetchPRLabels(2).Return([]string{"Patch", "patch"}, nil)
+		mockFetcher.EXPECT().FetchPRLabels(3).Return([]string{"minor change", "patch", "semver:minor"}, nil)
+		rule, err := GlobLabelRule("semver:*", VersionChangeMinor)
+		require.NoError(t, err)
+		cfg := &Config{
+			PRLabelFetcher: mockFetcher,
+			LabelRules:     []*LabelRule{rule},
+			StrictLabels:   true,
+		}
+		wantErr := &PRConflictingLabelErr{
+			IDs: []int{1, 3},
+			Labels: map[int][]string{
+				1: {"non-production change", "breaking change"},
+				3: {"minor change", "patch", "semver:minor"},
+			},
+		}
+		got, err := cfg.PRVersionChange(3, 2, 1)
+		require.EqualError(t, err, "one or more PRs have conflicting labels")
+		require.Equal(t, wantErr, err)
+		require.Equal(t, VersionChangeNone, got)
+	})
+
+	t.Run("conflicting labels without StrictLabels", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"Non-Production Change", "Breaking Change"}, nil)
+		cfg := &Config{
+			PRLabelFetcher: mockFetcher,
+		}
+		got, err := cfg.PRVersionChange(1)
+		require.NoError(t, err)
+		require.Equal(t, VersionChangeMajor, got)
+	})
+
+	t.Run("no conflicts with StrictLabels", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"Minor Change", "foo"}, nil)
+		mockFetcher.EXPECT().FetchPRLabels(2).Return([]string{"patch", "Patch"}, nil)
+		mockFetcher.EXPECT().FetchPRLabels(3).Return(nil, nil)
+		cfg := &Config{
+			PRLabelFetcher: mockFetcher,
+			StrictLabels:   true,
+		}
+		got, err := cfg.PRVersionChange(1, 2, 3)
+		require.NoError(t, err)
+		require.Equal(t, VersionChangeMinor, got)
+	})
+
 	t.Run("fetcher error", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		t.Cleanup(ctrl.Finish)
@@ -227,6 +520,101 @@ func TestConfig_PRVersionChange(t *testing.T) {
 	})
 }
 
+func TestConfig_PRVersionChangeContext(t *testing.T) {
+	t.Run("uses context", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		type ctxKey struct{}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "foo")
+		mockFetcher := mocks.NewMockContextPRLabelFetcher(ctrl)
+		fetchLabels := func(labels ...string) func(context.Context, int) ([]string, error) {
+			return func(gotCtx context.Context, _ int) ([]string, error) {
+				require.Equal(t, "foo", gotCtx.Value(ctxKey{}))
+				return labels, nil
+			}
+		}
+		mockFetcher.EXPECT().FetchPRLabelsContext(gomock.Any(), 1).DoAndReturn(fetchLabels("patch"))
+		mockFetcher.EXPECT().FetchPRLabelsContext(gomock.Any(), 2).DoAndReturn(fetchLabels("minor change"))
+		cfg := &Config{
+			PRLabelFetcher: FromContextFetcher(mockFetcher),
+		}
+		got, err := cfg.PRVersionChangeContext(ctx, 1, 2)
+		require.NoError(t, err)
+		require.Equal(t, VersionChangeMinor, got)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		cfg := &Config{
+			PRLabelFetcher: mockFetcher,
+		}
+		_, err := cfg.PRVersionChangeContext(ctx, 1, 2)
+		require.Equal(t, &PRLabelFetcherErr{err: context.Canceled}, err)
+	})
+}
+
+func TestConfig_NextVersionContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	ctx := context.Background()
+	mockFetcher := mocks.NewMockContextPRLabelFetcher(ctrl)
+	mockFetcher.EXPECT().FetchPRLabelsContext(gomock.Any(), 1).Return([]string{"breaking change"}, nil)
+	cfg := &Config{
+		PRLabelFetcher: FromContextFetcher(mockFetcher),
+	}
+	got, err := cfg.NextVersionContext(ctx, "v1.2.3", 1)
+	require.NoError(t, err)
+	require.Equal(t, "v2.0.0", got)
+}
+
+func TestConfig_NextVersionForRefs(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		ctx := context.Background()
+		mockDiscoverer := mocks.NewMockPRDiscoverer(ctrl)
+		mockDiscoverer.EXPECT().DiscoverPRs(ctx, "v1.2.3", "main").Return([]int{1, 2}, nil)
+		mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"patch"}, nil)
+		mockFetcher.EXPECT().FetchPRLabels(2).Return([]string{"minor change"}, nil)
+		cfg := &Config{
+			PRLabelFetcher: mockFetcher,
+			PRDiscoverer:   mockDiscoverer,
+		}
+		got, err := cfg.NextVersionForRefs(ctx, "v1.2.3", "v1.2.3", "main")
+		require.NoError(t, err)
+		require.Equal(t, "v1.3.0", got)
+	})
+
+	t.Run("discoverer error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		ctx := context.Background()
+		mockDiscoverer := mocks.NewMockPRDiscoverer(ctrl)
+		mockDiscoverer.EXPECT().DiscoverPRs(ctx, "v1.2.3", "main").Return(nil, assert.AnError)
+		cfg := &Config{
+			PRLabelFetcher: mocks.NewMockPRLabelFetcher(ctrl),
+			PRDiscoverer:   mockDiscoverer,
+		}
+		_, err := cfg.NextVersionForRefs(ctx, "v1.2.3", "v1.2.3", "main")
+		require.Equal(t, &PRDiscovererErr{err: assert.AnError}, err)
+		require.Equal(t, "error from PRDiscoverer", err.Error())
+		require.Equal(t, assert.AnError, errors.Unwrap(err))
+	})
+
+	t.Run("nil discoverer", func(t *testing.T) {
+		cfg := new(Config)
+		require.Panics(t, func() {
+			_, err := cfg.NextVersionForRefs(context.Background(), "v1.2.3", "v1.2.3", "main")
+			require.NoError(t, err)
+		})
+	})
+}
+
 func TestConfig_NextVersion(t *testing.T) {
 	t.Run("no change", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -292,6 +680,20 @@ func TestConfig_NextVersion(t *testing.T) {
 		require.Equal(t, "v2.0.0", got)
 	})
 
+	t.Run("pre-release", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"minor change"}, nil)
+		cfg := &Config{
+			PRLabelFetcher: mockFetcher,
+			PreRelease:     "rc",
+		}
+		got, err := cfg.NextVersion("v1.2.3", 1)
+		require.NoError(t, err)
+		require.Equal(t, "v1.3.0-rc.1", got)
+	})
+
 	t.Run("invalid current version", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		t.Cleanup(ctrl.Finish)
@@ -327,10 +729,10 @@ func TestConfig_NextVersion(t *testing.T) {
 	})
 }
 
-func Test_nextVersion(t *testing.T) {
+func TestConfig_nextVersion(t *testing.T) {
 	mustNextVersion := func(prev string, bump VersionChange) string {
 		t.Helper()
-		got, err := nextVersion(prev, bump)
+		got, err := new(Config).nextVersion(prev, bump)
 		require.NoError(t, err)
 		return got
 	}
@@ -342,3 +744,124 @@ func Test_nextVersion(t *testing.T) {
 	require.Equal(t, "v1.1.0", mustNextVersion("v1", VersionChangeMinor))
 	require.Equal(t, "v0.1.0", mustNextVersion("v0", VersionChangeMinor))
 }
+
+func TestConfig_nextVersion_preRelease(t *testing.T) {
+	for _, td := range []struct {
+		name       string
+		preRelease string
+		prev       string
+		bump       VersionChange
+		want       string
+		wantErr    bool
+	}{
+		{name: "start minor line", preRelease: "rc", prev: "v1.2.3", bump: VersionChangeMinor, want: "v1.3.0-rc.1"},
+		{name: "start patch line", preRelease: "rc", prev: "v1.2.3", bump: VersionChangePatch, want: "v1.2.4-rc.1"},
+		{name: "start major line", preRelease: "rc", prev: "v1.2.3", bump: VersionChangeMajor, want: "v2.0.0-rc.1"},
+		{name: "no change from release", preRelease: "rc", prev: "v1.2.3", bump: VersionChangeNone, want: "v1.2.3"},
+		{name: "no v prefix", preRelease: "rc", prev: "1.2.3", bump: VersionChangeMinor, want: "1.3.0-rc.1"},
+		{name: "increment", preRelease: "rc", prev: "v1.3.0-rc.1", bump: VersionChangeMinor, want: "v1.3.0-rc.2"},
+		{name: "increment with smaller change", preRelease: "rc", prev: "v1.3.0-rc.2", bump: VersionChangePatch, want: "v1.3.0-rc.3"},
+		{name: "increment past 9", preRelease: "rc", prev: "v1.3.0-rc.9", bump: VersionChangePatch, want: "v1.3.0-rc.10"},
+		{name: "no change on pre-release line", preRelease: "rc", prev: "v1.3.0-rc.2", bump: VersionChangeNone, want: "v1.3.0-rc.2"},
+		{name: "larger change starts new line", preRelease: "rc", prev: "v1.3.0-rc.2", bump: VersionChangeMajor, want: "v2.0.0-rc.1"},
+		{name: "minor after patch line", preRelease: "rc", prev: "v1.2.4-rc.1", bump: VersionChangeMinor, want: "v1.3.0-rc.1"},
+		{name: "major line absorbs minor", preRelease: "beta", prev: "v2.0.0-beta.3", bump: VersionChangeMinor, want: "v2.0.0-beta.4"},
+		{name: "switch identifier", preRelease: "rc", prev: "v1.3.0-beta.4", bump: VersionChangePatch, want: "v1.3.0-rc.1"},
+		{name: "bare identifier", preRelease: "rc", prev: "v1.3.0-rc", bump: VersionChangePatch, want: "v1.3.0-rc.1"},
+		{name: "drops metadata", preRelease: "rc", prev: "v1.3.0-rc.1+build.5", bump: VersionChangePatch, want: "v1.3.0-rc.2"},
+		{name: "identifier would go backwards", preRelease: "alpha", prev: "v1.3.0-beta.1", bump: VersionChangePatch, wantErr: true},
+		{name: "invalid identifier", preRelease: "r_c", prev: "v1.2.3", bump: VersionChangePatch, wantErr: true},
+		{name: "promote", prev: "v1.3.0-rc.2", bump: VersionChangeMinor, want: "v1.3.0"},
+		{name: "promote with no change", prev: "v1.3.0-rc.2", bump: VersionChangeNone, want: "v1.3.0"},
+		{name: "promote with smaller change", prev: "v2.0.0-rc.2", bump: VersionChangePatch, want: "v2.0.0"},
+		{name: "promote with larger change", prev: "v1.3.0-rc.2", bump: VersionChangeMajor, want: "v2.0.0"},
+		{name: "promote patch line with minor change", prev: "v1.2.4-rc.1", bump: VersionChangeMinor, want: "v1.3.0"},
+	} {
+		td := td
+		t.Run(td.name, func(t *testing.T) {
+			cfg := &Config{PreRelease: td.preRelease}
+			got, err := cfg.nextVersion(td.prev, td.bump)
+			if td.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, td.want, got)
+		})
+	}
+}
+
+func TestConfig_nextVersion_initialDevelopment(t *testing.T) {
+	for _, td := range []struct {
+		name       string
+		preRelease string
+		prev       string
+		bump       VersionChange
+		want       string
+	}{
+		{name: "major is minor", prev: "v0.4.2", bump: VersionChangeMajor, want: "v0.5.0"},
+		{name: "minor is patch", prev: "v0.4.2", bump: VersionChangeMinor, want: "v0.4.3"},
+		{name: "patch is patch", prev: "v0.4.2", bump: VersionChangePatch, want: "v0.4.3"},
+		{name: "none is none", prev: "v0.4.2", bump: VersionChangeNone, want: "v0.4.2"},
+		{name: "v1 is unaffected", prev: "v1.4.2", bump: VersionChangeMajor, want: "v2.0.0"},
+		{name: "pre-release", preRelease: "rc", prev: "v0.4.2", bump: VersionChangeMajor, want: "v0.5.0-rc.1"},
+		{name: "pre-release increment", preRelease: "rc", prev: "v0.5.0-rc.1", bump: VersionChangeMajor, want: "v0.5.0-rc.2"},
+	} {
+		td := td
+		t.Run(td.name, func(t *testing.T) {
+			cfg := &Config{
+				InitialDevelopment: true,
+				PreRelease:         td.preRelease,
+			}
+			got, err := cfg.nextVersion(td.prev, td.bump)
+			require.NoError(t, err)
+			require.Equal(t, td.want, got)
+		})
+	}
+}
+
+func TestConfig_Graduate(t *testing.T) {
+	for _, td := range []struct {
+		name       string
+		preRelease string
+		prev       string
+		want       string
+		wantErr    bool
+	}{
+		{name: "v0", prev: "v0.4.2", want: "v1.0.0"},
+		{name: "no v prefix", prev: "0.4.2", want: "1.0.0"},
+		{name: "v0 pre-release", prev: "v0.5.0-rc.1", want: "v1.0.0"},
+		{name: "start v1 pre-release", preRelease: "rc", prev: "v0.4.2", want: "v1.0.0-rc.1"},
+		{name: "increment v1 pre-release", preRelease: "rc", prev: "v1.0.0-rc.1", want: "v1.0.0-rc.2"},
+		{name: "promote v1 pre-release", prev: "v1.0.0-rc.2", want: "v1.0.0"},
+		{name: "already v1", prev: "v1.0.0", wantErr: true},
+		{name: "already v2", prev: "v2.1.0-rc.1", wantErr: true},
+		{name: "invalid", prev: "limabeans", wantErr: true},
+	} {
+		td := td
+		t.Run(td.name, func(t *testing.T) {
+			cfg := &Config{
+				InitialDevelopment: true,
+				PreRelease:         td.preRelease,
+			}
+			got, err := cfg.Graduate(td.prev)
+			if td.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, td.want, got)
+		})
+	}
+}
+
+func TestConfig_IncrementVersion(t *testing.T) {
+	cfg := &Config{PreRelease: "rc"}
+	got, err := cfg.IncrementVersion("v1.2.3", VersionChangeMinor)
+	require.NoError(t, err)
+	require.Equal(t, "v1.3.0-rc.1", got)
+
+	got, err = cfg.IncrementVersion("v1.2.3", VersionChange(12))
+	require.EqualError(t, err, "12 is not a valid VersionChange")
+	require.Equal(t, "", got)
+}
@@ -0,0 +1,143 @@
+// Package git reads pull request information from a local git repository so it can be used without API calls.
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+var (
+	// mergeSubjectExp matches GitHub's merge commit subject: "Merge pull request #123 from owner/branch"
+	mergeSubjectExp = regexp.MustCompile(`^Merge pull request #(\d+) from `)
+
+	// squashSubjectExp matches GitHub's squash merge subject: "PR title (#123)"
+	squashSubjectExp = regexp.MustCompile(`\(#(\d+)\)$`)
+)
+
+type prDiscoverer struct {
+	dir string
+}
+
+// NewPRDiscoverer returns a PRDiscoverer that reads the history of the git repository in dir. Pull request numbers
+// come from the subjects of merge commits ("Merge pull request #123 from ...") and squash merges ("... (#123)").
+func NewPRDiscoverer(dir string) conventionalpulls.PRDiscoverer {
+	return &prDiscoverer{
+		dir: dir,
+	}
+}
+
+func (d *prDiscoverer) DiscoverPRs(ctx context.Context, baseRef, headRef string) ([]int, error) {
+	// --first-parent skips the commits on merged branches, which are not merges themselves
+	out, err := runGit(ctx, d.dir, "log", "--first-parent", "--format=%s", baseRef+".."+headRef)
+	if err != nil {
+		return nil, err
+	}
+	found := map[int]bool{}
+	for _, subject := range strings.Split(out, "\n") {
+		id, ok := subjectPRNumber(subject)
+		if ok {
+			found[id] = true
+		}
+	}
+	prIDs := make([]int, 0, len(found))
+	for id := range found {
+		prIDs = append(prIDs, id)
+	}
+	sort.Ints(prIDs)
+	return prIDs, nil
+}
+
+type tagLister struct {
+	dir string
+}
+
+// NewTagLister returns a TagLister for the tags in the git repository in dir
+func NewTagLister(dir string) conventionalpulls.TagLister {
+	return &tagLister{
+		dir: dir,
+	}
+}
+
+func (l *tagLister) ListTags(ctx context.Context) ([]string, error) {
+	out, err := runGit(ctx, l.dir, "tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+type commitLister struct {
+	dir string
+}
+
+// NewCommitLister returns a CommitLister for the git repository in dir. Merge commits are skipped.
+func NewCommitLister(dir string) conventionalpulls.CommitLister {
+	return &commitLister{
+		dir: dir,
+	}
+}
+
+func (l *commitLister) ListCommits(ctx context.Context, baseRef, headRef string) ([]conventionalpulls.Commit, error) {
+	// each commit is "<sha>\x00<message>\x1e"
+	out, err := runGit(ctx, l.dir, "log", "--reverse", "--topo-order", "--no-merges", "--format=%H%x00%B%x1e", baseRef+".."+headRef)
+	if err != nil {
+		return nil, err
+	}
+	commits := []conventionalpulls.Commit{}
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+		parts := strings.SplitN(record, "\x00", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unexpected git log output %q", record)
+		}
+		commits = append(commits, conventionalpulls.Commit{
+			SHA:     parts[0],
+			Message: strings.TrimSpace(parts[1]),
+		})
+	}
+	return commits, nil
+}
+
+// subjectPRNumber returns the pull request number from a merge or squash merge commit subject
+func subjectPRNumber(subject string) (int, bool) {
+	subject = strings.TrimSpace(subject)
+	match := mergeSubjectExp.FindStringSubmatch(subject)
+	if match == nil {
+		match = squashSubjectExp.FindStringSubmatch(subject)
+	}
+	if match == nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,216 @@
+package git
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+)
+
+// testRepo is a temporary git repository
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "conventionalpulls")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(dir))
+	})
+	repo := &testRepo{t: t, dir: dir}
+	repo.git("init", "-q")
+	repo.git("checkout", "-q", "-b", "main")
+	repo.commit("initial commit")
+	return repo
+}
+
+func (r *testRepo) git(args ...string) {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test",
+		"GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test",
+		"GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"HOME="+r.dir,
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(r.t, err, string(out))
+}
+
+func (r *testRepo) commit(message string) {
+	r.t.Helper()
+	r.git("commit", "-q", "--allow-empty", "-m", message)
+}
+
+// mergeBranch creates branch with a commit and merges it into main with message
+func (r *testRepo) mergeBranch(branch, message string) {
+	r.t.Helper()
+	r.git("checkout", "-q", "-b", branch)
+	r.commit("work on " + branch + " (#999)")
+	r.git("checkout", "-q", "main")
+	r.git("merge", "-q", "--no-ff", "-m", message, branch)
+}
+
+func Test_subjectPRNumber(t *testing.T) {
+	for _, td := range []struct {
+		subject string
+		want    int
+		wantOK  bool
+	}{
+		{subject: "Merge pull request #123 from foo/bar", want: 123, wantOK: true},
+		{subject: "add a thing (#45)", want: 45, wantOK: true},
+		{subject: "add a thing (#45)  ", want: 45, wantOK: true},
+		{subject: "fix #45 in the middle", wantOK: false},
+		{subject: "(#45) at the start", wantOK: false},
+		{subject: "Merge branch 'main' into foo", wantOK: false},
+		{subject: "", wantOK: false},
+	} {
+		got, gotOK := subjectPRNumber(td.subject)
+		require.Equal(t, td.wantOK, gotOK, td.subject)
+		require.Equal(t, td.want, got, td.subject)
+	}
+}
+
+func TestNewPRDiscoverer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo := newTestRepo(t)
+		repo.commit("before the tag (#1)")
+		repo.git("tag", "v1.0.0")
+		repo.commit("squashed (#12)")
+		repo.commit("pushed directly")
+		repo.git("checkout", "-q", "-b", "feature")
+		repo.commit("feature work")
+		repo.git("checkout", "-q", "main")
+		repo.git("merge", "-q", "--no-ff", "-m", "Merge pull request #7 from foo/feature", "feature")
+		repo.commit("squashed again (#12)")
+		repo.git("tag", "v1.1.0")
+		repo.commit("after the tag (#20)")
+
+		discoverer := NewPRDiscoverer(repo.dir)
+		got, err := discoverer.DiscoverPRs(context.Background(), "v1.0.0", "v1.1.0")
+		require.NoError(t, err)
+		require.Equal(t, []int{7, 12}, got)
+
+		got, err = discoverer.DiscoverPRs(context.Background(), "v1.0.0", "main")
+		require.NoError(t, err)
+		require.Equal(t, []int{7, 12, 20}, got)
+	})
+
+	t.Run("ignores commits on merged branches", func(t *testing.T) {
+		repo := newTestRepo(t)
+		repo.git("tag", "v1.0.0")
+		repo.mergeBranch("feature", "Merge pull request #3 from foo/feature")
+		got, err := NewPRDiscoverer(repo.dir).DiscoverPRs(context.Background(), "v1.0.0", "main")
+		require.NoError(t, err)
+		require.Equal(t, []int{3}, got)
+	})
+
+	t.Run("no pulls", func(t *testing.T) {
+		repo := newTestRepo(t)
+		repo.git("tag", "v1.0.0")
+		got, err := NewPRDiscoverer(repo.dir).DiscoverPRs(context.Background(), "v1.0.0", "main")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		repo := newTestRepo(t)
+		got, err := NewPRDiscoverer(repo.dir).DiscoverPRs(context.Background(), "v1.0.0", "main")
+		require.Error(t, err)
+		require.Empty(t, got)
+	})
+}
+
+func TestNewTagLister(t *testing.T) {
+	t.Run("tags", func(t *testing.T) {
+		repo := newTestRepo(t)
+		repo.git("tag", "v1.0.0")
+		repo.commit("second")
+		repo.git("tag", "-a", "-m", "annotated", "v1.1.0")
+		repo.git("tag", "mymod/v0.1.0")
+		got, err := NewTagLister(repo.dir).ListTags(context.Background())
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"v1.0.0", "v1.1.0", "mymod/v0.1.0"}, got)
+	})
+
+	t.Run("no tags", func(t *testing.T) {
+		repo := newTestRepo(t)
+		got, err := NewTagLister(repo.dir).ListTags(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("not a repository", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "conventionalpulls")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, os.RemoveAll(dir))
+		})
+		_, err = NewTagLister(dir).ListTags(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("with TagResolver", func(t *testing.T) {
+		repo := newTestRepo(t)
+		repo.git("tag", "v1.0.0")
+		repo.git("tag", "v1.2.0")
+		repo.git("tag", "v1.3.0-rc.1")
+		resolver := &conventionalpulls.TagResolver{
+			TagLister:         NewTagLister(repo.dir),
+			Prefix:            "v",
+			IgnorePreReleases: true,
+		}
+		tag, version, err := resolver.LatestTag(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "v1.2.0", tag)
+		require.Equal(t, "1.2.0", version)
+	})
+}
+
+func TestNewCommitLister(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo := newTestRepo(t)
+		repo.git("tag", "v1.0.0")
+		repo.commit("feat: add widgets")
+		repo.commit("fix(api): stop crashing\n\nIt crashed a lot.\n\nBREAKING CHANGE: widgets are gone\nRefs #12")
+		repo.mergeBranch("feature", "Merge pull request #3 from foo/feature")
+
+		got, err := NewCommitLister(repo.dir).ListCommits(context.Background(), "v1.0.0", "main")
+		require.NoError(t, err)
+		messages := make([]string, len(got))
+		for i, commit := range got {
+			require.Len(t, commit.SHA, 40)
+			messages[i] = commit.Message
+		}
+		require.Equal(t, []string{
+			"feat: add widgets",
+			"fix(api): stop crashing\n\nIt crashed a lot.\n\nBREAKING CHANGE: widgets are gone\nRefs #12",
+			"work on feature (#999)",
+		}, messages)
+	})
+
+	t.Run("no commits", func(t *testing.T) {
+		repo := newTestRepo(t)
+		repo.git("tag", "v1.0.0")
+		got, err := NewCommitLister(repo.dir).ListCommits(context.Background(), "v1.0.0", "main")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		repo := newTestRepo(t)
+		got, err := NewCommitLister(repo.dir).ListCommits(context.Background(), "v1.0.0", "main")
+		require.Error(t, err)
+		require.Nil(t, got)
+	})
+}
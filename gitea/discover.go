@@ -0,0 +1,90 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+type prDiscoverer struct {
+	client *client
+}
+
+// NewPRDiscoverer returns a PRDiscoverer that uses the compare API to find the commits between two refs and returns
+// the merged pull requests whose merge commits are among them. Gitea has no API for the pull requests associated with
+// a commit, so closed pull requests are checked from the most recently updated until one was last updated before the
+// oldest commit between the refs.
+func NewPRDiscoverer(owner, repo string, opt ...Option) conventionalpulls.PRDiscoverer {
+	return &prDiscoverer{
+		client: newClient(owner, repo, opt),
+	}
+}
+
+func (d *prDiscoverer) DiscoverPRs(ctx context.Context, baseRef, headRef string) ([]int, error) {
+	commits, err := d.compareCommits(ctx, baseRef, headRef)
+	if err != nil {
+		return nil, err
+	}
+	prIDs := []int{}
+	if len(commits) == 0 {
+		return prIDs, nil
+	}
+	inRange := make(map[string]bool, len(commits))
+	oldest := commits[0].Commit.Committer.Date
+	for _, commit := range commits {
+		inRange[commit.SHA] = true
+		if commit.Commit.Committer.Date.Before(oldest) {
+			oldest = commit.Commit.Committer.Date
+		}
+	}
+	query := url.Values{}
+	query.Set("state", "closed")
+	query.Set("sort", "recentupdate")
+	query.Set("limit", "50")
+	next := d.client.repoURL("/pulls", query)
+	for next != "" {
+		var pulls []pullRequest
+		next, err = d.client.get(ctx, next, &pulls)
+		if err != nil {
+			return nil, err
+		}
+		for _, pull := range pulls {
+			// a pull request merged between the refs was updated when it was merged
+			if pull.UpdatedAt.Before(oldest) {
+				next = ""
+				break
+			}
+			if pull.Merged && inRange[pull.MergeCommitSHA] {
+				prIDs = append(prIDs, pull.Number)
+			}
+		}
+	}
+	sort.Ints(prIDs)
+	return prIDs, nil
+}
+
+type compareCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+}
+
+// compareCommits returns all commits that are in headRef but not baseRef
+func (d *prDiscoverer) compareCommits(ctx context.Context, baseRef, headRef string) ([]compareCommit, error) {
+	var comparison struct {
+		Commits []compareCommit `json:"commits"`
+	}
+	p := fmt.Sprintf("/compare/%s...%s", url.PathEscape(baseRef), url.PathEscape(headRef))
+	_, err := d.client.get(ctx, d.client.repoURL(p, nil), &comparison)
+	if err != nil {
+		return nil, err
+	}
+	return comparison.Commits, nil
+}
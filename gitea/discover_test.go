@@ -0,0 +1,85 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls/internal/rest/resttest"
+)
+
+func TestNewPRDiscoverer(t *testing.T) {
+	pull := func(number int, merged bool, mergeSHA, updated string) map[string]interface{} {
+		return map[string]interface{}{
+			"number":           number,
+			"merged":           merged,
+			"merge_commit_sha": mergeSHA,
+			"updated_at":       "2021-01-" + updated + "T12:00:00Z",
+		}
+	}
+	commit := func(sha, date string) map[string]interface{} {
+		return map[string]interface{}{
+			"sha":    sha,
+			"commit": map[string]interface{}{"committer": map[string]string{"date": "2021-01-" + date + "T00:00:00Z"}},
+		}
+	}
+	pages := [][]map[string]interface{}{
+		{pull(9, false, "", "06"), pull(8, true, "c3", "05")},
+		{pull(7, true, "zz", "04"), pull(6, true, "a1", "02")},
+		{pull(5, true, "yy", "01"), pull(4, true, "ww", "01")},
+		{pull(3, true, "xx", "01")},
+	}
+	var serverURL string
+	var requested []int
+	server := testServer(t, map[string]http.HandlerFunc{
+		"/api/v1/repos/foo/bar/compare/v1.0.0...main": resttest.JSONHandler(map[string]interface{}{
+			"commits": []map[string]interface{}{commit("a1", "02"), commit("b2", "03"), commit("c3", "04")},
+		}),
+		"/api/v1/repos/foo/bar/compare/v1.1.0...main": resttest.JSONHandler(map[string]interface{}{
+			"commits": []map[string]interface{}{},
+		}),
+		"/api/v1/repos/foo/bar/pulls": func(w http.ResponseWriter, r *http.Request) {
+			page := 1
+			if r.URL.Query().Get("page") != "" {
+				_, err := fmt.Sscan(r.URL.Query().Get("page"), &page)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+			requested = append(requested, page)
+			if r.URL.Query().Get("state") != "closed" || r.URL.Query().Get("sort") != "recentupdate" {
+				http.Error(w, `{"message":"unexpected query"}`, http.StatusBadRequest)
+				return
+			}
+			if page < len(pages) {
+				w.Header().Set("Link", fmt.Sprintf(
+					`<%s/api/v1/repos/foo/bar/pulls?state=closed&sort=recentupdate&page=%d>; rel="next",<%s/api/v1/repos/foo/bar/pulls?state=closed&sort=recentupdate&page=%d>; rel="last"`,
+					serverURL, page+1, serverURL, len(pages),
+				))
+			}
+			resttest.JSONHandler(pages[page-1])(w, r)
+		},
+	})
+	serverURL = server.URL
+
+	discoverer := NewPRDiscoverer("foo", "bar", testOptions(server)...)
+	got, err := discoverer.DiscoverPRs(context.Background(), "v1.0.0", "main")
+	require.NoError(t, err)
+	require.Equal(t, []int{6, 8}, got)
+	// pull requests updated before the oldest commit are never merged in the range, so page 4 isn't requested
+	require.Equal(t, []int{1, 2, 3}, requested)
+
+	t.Run("empty range", func(t *testing.T) {
+		got, err := discoverer.DiscoverPRs(context.Background(), "v1.1.0", "main")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := discoverer.DiscoverPRs(context.Background(), "v0.1.0", "main")
+		require.EqualError(t, err, "Gitea returned 404 Not Found")
+	})
+}
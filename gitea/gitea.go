@@ -0,0 +1,176 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/conventionalpulls/internal/rest"
+)
+
+// DefaultBaseURL is the base URL for gitea.com's API
+const DefaultBaseURL = "https://gitea.com/api/v1"
+
+// Option configures requests to Gitea
+type Option func(c *client)
+
+// WithBaseURL sets the base URL for a Gitea or Forgejo API. It is usually "https://<host>/api/v1". The default is
+// DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *client) {
+		c.SetBaseURL(baseURL)
+	}
+}
+
+// WithToken authenticates requests with an access token
+func WithToken(token string) Option {
+	return func(c *client) {
+		c.Auth = func(req *http.Request) {
+			req.Header.Set("Authorization", "token "+token)
+		}
+	}
+}
+
+// WithHTTPClient sets the http.Client used for requests. The default is http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+type client struct {
+	rest.Client
+	owner string
+	repo  string
+}
+
+func newClient(owner, repo string, opts []Option) *client {
+	c := &client{
+		Client: rest.Client{
+			BaseURL: DefaultBaseURL,
+			Service: "Gitea",
+		},
+		owner: owner,
+		repo:  repo,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// repoURL returns the URL for the repository path p
+func (c *client) repoURL(p string, query url.Values) string {
+	return c.URL(fmt.Sprintf("/repos/%s/%s%s", url.PathEscape(c.owner), url.PathEscape(c.repo), p), query)
+}
+
+// linkNextExp matches the next page's URL in a Link header
+var linkNextExp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// get sends a GET request to u and decodes the JSON response into v. It returns the next page's URL from the Link
+// header, or "" on the last page.
+func (c *client) get(ctx context.Context, u string, v interface{}) (string, error) {
+	header, err := c.Get(ctx, u, v)
+	if err != nil {
+		return "", err
+	}
+	match := linkNextExp.FindStringSubmatch(header.Get("Link"))
+	if match == nil {
+		return "", nil
+	}
+	return match[1], nil
+}
+
+type pullRequest struct {
+	Number         int       `json:"number"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body"`
+	HTMLURL        string    `json:"html_url"`
+	Merged         bool      `json:"merged"`
+	MergeCommitSHA string    `json:"merge_commit_sha"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	User           struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+func (p *pullRequest) labelNames() []string {
+	labels := make([]string, len(p.Labels))
+	for i, label := range p.Labels {
+		labels[i] = label.Name
+	}
+	return labels
+}
+
+func (c *client) getPull(ctx context.Context, index int) (*pullRequest, error) {
+	var pull pullRequest
+	_, err := c.get(ctx, c.repoURL(fmt.Sprintf("/pulls/%d", index), nil), &pull)
+	if err != nil {
+		return nil, err
+	}
+	return &pull, nil
+}
+
+type prLabelFetcher struct {
+	client *client
+	getCtx func() context.Context
+}
+
+// NewPRLabelFetcher returns a PRLabelFetcher that queries a Gitea or Forgejo server for PR Labels. ctx is the context
+// for FetchPRLabels' requests.
+func NewPRLabelFetcher(ctx context.Context, owner, repo string, opt ...Option) conventionalpulls.PRLabelFetcher {
+	return &prLabelFetcher{
+		client: newClient(owner, repo, opt),
+		getCtx: func() context.Context {
+			return ctx
+		},
+	}
+}
+
+func (f *prLabelFetcher) FetchPRLabels(id int) ([]string, error) {
+	return f.FetchPRLabelsContext(f.getCtx(), id)
+}
+
+func (f *prLabelFetcher) FetchPRLabelsContext(ctx context.Context, id int) ([]string, error) {
+	pull, err := f.client.getPull(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return pull.labelNames(), nil
+}
+
+type prFetcher struct {
+	client *client
+}
+
+// NewPRFetcher returns a PRFetcher that queries a Gitea or Forgejo server for pull requests
+func NewPRFetcher(owner, repo string, opt ...Option) conventionalpulls.PRFetcher {
+	return &prFetcher{
+		client: newClient(owner, repo, opt),
+	}
+}
+
+func (f *prFetcher) FetchPR(ctx context.Context, id int) (*conventionalpulls.PullRequest, error) {
+	pull, err := f.client.getPull(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &conventionalpulls.PullRequest{
+		Number: pull.Number,
+		Title:  pull.Title,
+		Body:   pull.Body,
+		Author: pull.User.Login,
+		URL:    pull.HTMLURL,
+		Labels: pull.labelNames(),
+	}, nil
+}
+
+// ResponseErr is an error indicating the server responded with a non-2xx status
+type ResponseErr = rest.ResponseErr
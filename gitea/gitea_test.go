@@ -0,0 +1,108 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/conventionalpulls/internal/rest/resttest"
+)
+
+// testServer returns a Gitea stand-in that serves routes by escaped path
+func testServer(t *testing.T, routes map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	return resttest.NewServer(t, "Authorization", "token "+resttest.Token, routes)
+}
+
+func testOptions(server *httptest.Server) []Option {
+	return []Option{
+		WithBaseURL(server.URL + "/api/v1/"),
+		WithToken(resttest.Token),
+		WithHTTPClient(server.Client()),
+	}
+}
+
+var testPull = map[string]interface{}{
+	"number":   12,
+	"title":    "feat: add widgets",
+	"body":     "adds widgets",
+	"html_url": "https://forgejo.example.com/foo/bar/pulls/12",
+	"user":     map[string]string{"login": "octocat"},
+	"labels":   []map[string]string{{"name": "label 1"}, {"name": "label 2"}},
+}
+
+func TestNewPRLabelFetcher(t *testing.T) {
+	server := testServer(t, map[string]http.HandlerFunc{
+		"/api/v1/repos/foo/bar/pulls/12": resttest.JSONHandler(testPull),
+		"/api/v1/repos/foo/bar/pulls/13": resttest.JSONHandler(map[string]interface{}{"number": 13}),
+	})
+
+	t.Run("has labels", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(context.Background(), "foo", "bar", testOptions(server)...)
+		got, err := fetcher.FetchPRLabels(12)
+		require.NoError(t, err)
+		require.Equal(t, []string{"label 1", "label 2"}, got)
+	})
+
+	t.Run("no labels", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(context.Background(), "foo", "bar", testOptions(server)...)
+		got, err := fetcher.FetchPRLabels(13)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(context.Background(), "foo", "bar", testOptions(server)...)
+		got, err := fetcher.FetchPRLabels(14)
+		require.EqualError(t, err, "Gitea returned 404 Not Found")
+		require.Equal(t, &ResponseErr{
+			Service:    "Gitea",
+			StatusCode: http.StatusNotFound,
+			Body:       `{"message":"not found"}`,
+		}, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(context.Background(), "foo", "bar", WithBaseURL(server.URL+"/api/v1"))
+		_, err := fetcher.FetchPRLabels(12)
+		require.IsType(t, &ResponseErr{}, err)
+		require.Equal(t, http.StatusUnauthorized, err.(*ResponseErr).StatusCode)
+	})
+
+	t.Run("FetchPRLabelsContext", func(t *testing.T) {
+		canceledCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fetcher := NewPRLabelFetcher(canceledCtx, "foo", "bar", testOptions(server)...)
+		ctxFetcher, ok := fetcher.(conventionalpulls.ContextPRLabelFetcher)
+		require.True(t, ok)
+		got, err := ctxFetcher.FetchPRLabelsContext(context.Background(), 12)
+		require.NoError(t, err)
+		require.Equal(t, []string{"label 1", "label 2"}, got)
+		_, err = ctxFetcher.FetchPRLabelsContext(canceledCtx, 12)
+		require.Error(t, err)
+	})
+}
+
+func TestNewPRFetcher(t *testing.T) {
+	server := testServer(t, map[string]http.HandlerFunc{
+		"/api/v1/repos/foo/bar/pulls/12": resttest.JSONHandler(testPull),
+	})
+	fetcher := NewPRFetcher("foo", "bar", testOptions(server)...)
+	got, err := fetcher.FetchPR(context.Background(), 12)
+	require.NoError(t, err)
+	require.Equal(t, &conventionalpulls.PullRequest{
+		Number: 12,
+		Title:  "feat: add widgets",
+		Body:   "adds widgets",
+		Author: "octocat",
+		URL:    "https://forgejo.example.com/foo/bar/pulls/12",
+		Labels: []string{"label 1", "label 2"},
+	}, got)
+
+	_, err = fetcher.FetchPR(context.Background(), 13)
+	require.IsType(t, &ResponseErr{}, err)
+}
@@ -0,0 +1,203 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/octo-go"
+	"github.com/willabides/octo-go/components"
+)
+
+// DefaultCheckName is the default name of LabelChecker's check run or commit status
+const DefaultCheckName = "conventionalpulls/labels"
+
+// LabelCheck is the result of checking an open pull request's labels
+type LabelCheck struct {
+	PullRequest int
+	HeadSHA     string
+
+	// Passed is true when the pull request has the labels it needs to be merged
+	Passed bool
+
+	// Err is the *conventionalpulls.PRMissingLabelErr or *conventionalpulls.PRConflictingLabelErr when the check
+	// didn't pass
+	Err error
+
+	// Title is a one line description of the result
+	Title string
+
+	// Summary is a Markdown explanation of the result including the accepted labels
+	Summary string
+
+	Report *conventionalpulls.PRReport
+}
+
+// Conclusion returns "success" or "failure". It is both a check run conclusion and a commit status state.
+func (c *LabelCheck) Conclusion() string {
+	if c.Passed {
+		return "success"
+	}
+	return "failure"
+}
+
+// LabelChecker checks open pull requests against a Config and reports the result as a check run or commit status so
+// pull requests without a version label can be blocked before they are merged.
+type LabelChecker struct {
+	// Name is the check run name or commit status context. Default is DefaultCheckName.
+	Name string
+
+	// DetailsURL is linked from the check run or commit status when it is set
+	DetailsURL string
+
+	// PRFetcher fetches the pull request whose labels are checked when it is set, for example a
+	// conventionalcommits.NewPRFetcher that adds a label for the pull request's title. The head commit is still
+	// fetched from GitHub. Default is the labels fetched with the head commit.
+	PRFetcher conventionalpulls.PRFetcher
+
+	config *conventionalpulls.Config
+	client octo.Client
+	owner  string
+	repo   string
+}
+
+// NewLabelChecker returns a LabelChecker for a GitHub repository. cfg.PRLabelFetcher isn't used. Labels are fetched
+// from the pull request along with its head commit unless LabelChecker.PRFetcher is set.
+func NewLabelChecker(cfg *conventionalpulls.Config, owner, repo string, opt ...octo.RequestOption) *LabelChecker {
+	return &LabelChecker{
+		config: cfg,
+		client: opt,
+		owner:  owner,
+		repo:   repo,
+	}
+}
+
+func (c *LabelChecker) name() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return DefaultCheckName
+}
+
+// Check fetches a pull request and checks its labels with Config.CheckLabels
+func (c *LabelChecker) Check(ctx context.Context, id int) (*LabelCheck, error) {
+	pull, err := c.client.PullsGet(ctx, &octo.PullsGetReq{
+		Owner:      c.owner,
+		Repo:       c.repo,
+		PullNumber: int64(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	labels, err := c.labels(ctx, id, pull.Data.Labels)
+	if err != nil {
+		return nil, err
+	}
+	report, err := c.config.CheckLabels(id, labels)
+	check := &LabelCheck{
+		PullRequest: id,
+		HeadSHA:     pull.Data.Head.Sha,
+		Passed:      err == nil,
+		Err:         err,
+		Report:      report,
+	}
+	var b strings.Builder
+	var missingErr *conventionalpulls.PRMissingLabelErr
+	var conflictErr *conventionalpulls.PRConflictingLabelErr
+	switch {
+	case err == nil:
+		change := "no version change"
+		if report.VersionChange != conventionalpulls.VersionChangeNone {
+			change = fmt.Sprintf("a %s version change", strings.ToLower(report.VersionChange.String()))
+		}
+		check.Title = "Labeled for " + change
+		fmt.Fprintf(&b, "#%d is labeled for %s.\n\n", id, change)
+		b.WriteString("| Label | Matched rule | Change |\n| --- | --- | --- |\n")
+		for _, match := range report.Matches {
+			fmt.Fprintf(&b, "| %s | %s `%s` | %s |\n",
+				conventionalpulls.MarkdownCell(match.Label), match.RuleType, conventionalpulls.MarkdownCell(match.Rule), match.VersionChange)
+		}
+	case errors.As(err, &missingErr):
+		check.Title = "Missing a version label"
+		fmt.Fprintf(&b, "#%d needs one of the accepted labels before it is merged.\n", id)
+	case errors.As(err, &conflictErr):
+		check.Title = "Conflicting version labels"
+		fmt.Fprintf(&b, "#%d has labels for different version changes: %s. Remove all but one of them.\n",
+			id, strings.Join(conflictErr.Labels[id], ", "))
+	default:
+		return nil, err
+	}
+	b.WriteString("\n### Accepted labels\n\n| Label | Change |\n| --- | --- |\n")
+	for _, accepted := range c.config.AcceptedLabels() {
+		rule := fmt.Sprintf("`%s`", conventionalpulls.MarkdownCell(accepted.Rule))
+		if accepted.RuleType != "label" {
+			rule = fmt.Sprintf("%s matching %s", accepted.RuleType, rule)
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", rule, accepted.VersionChange)
+	}
+	check.Summary = b.String()
+	return check, nil
+}
+
+// labels returns the labels to check for pull request id. pullLabels are the labels fetched with its head commit.
+func (c *LabelChecker) labels(ctx context.Context, id int, pullLabels []components.PullRequestLabelsItem) ([]string, error) {
+	if c.PRFetcher != nil {
+		pull, err := c.PRFetcher.FetchPR(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return pull.Labels, nil
+	}
+	labels := make([]string, len(pullLabels))
+	for i, label := range pullLabels {
+		labels[i] = label.Name
+	}
+	return labels, nil
+}
+
+// PostCheckRun creates a completed check run for check on the pull request's head commit. Check runs can only be
+// created with a GitHub App token such as GITHUB_TOKEN in GitHub Actions.
+func (c *LabelChecker) PostCheckRun(ctx context.Context, check *LabelCheck) error {
+	body := octo.ChecksCreateReqBody{
+		HeadSha:    octo.String(check.HeadSHA),
+		Name:       octo.String(c.name()),
+		Status:     octo.String("completed"),
+		Conclusion: octo.String(check.Conclusion()),
+		Output: &octo.ChecksCreateReqBodyOutput{
+			Title:   octo.String(check.Title),
+			Summary: octo.String(check.Summary),
+		},
+	}
+	if c.DetailsURL != "" {
+		body.DetailsUrl = octo.String(c.DetailsURL)
+	}
+	_, err := c.client.ChecksCreate(ctx, &octo.ChecksCreateReq{
+		Owner:          c.owner,
+		Repo:           c.repo,
+		RequestBody:    body,
+		AntiopePreview: true,
+	})
+	return err
+}
+
+// PostStatus creates a commit status for check on the pull request's head commit. Commit statuses have no room for
+// the summary, so only the title is posted. Use DetailsURL to link to an explanation.
+func (c *LabelChecker) PostStatus(ctx context.Context, check *LabelCheck) error {
+	body := octo.ReposCreateCommitStatusReqBody{
+		State:       octo.String(check.Conclusion()),
+		Context:     octo.String(c.name()),
+		Description: octo.String(check.Title),
+	}
+	if c.DetailsURL != "" {
+		body.TargetUrl = octo.String(c.DetailsURL)
+	}
+	_, err := c.client.ReposCreateCommitStatus(ctx, &octo.ReposCreateCommitStatusReq{
+		Owner:       c.owner,
+		Repo:        c.repo,
+		Sha:         check.HeadSHA,
+		RequestBody: body,
+	})
+	return err
+}
@@ -0,0 +1,194 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/octo-go"
+	"github.com/willabides/octo-go/components"
+	"github.com/willabides/octo-go/octotest"
+)
+
+func expectPull(server *octotest.Server, labels ...string) {
+	body := &octo.PullsGetResponseBody{
+		Number: 12,
+		Head:   components.PullRequestHead{Sha: testSHA},
+	}
+	for _, label := range labels {
+		body.Labels = append(body.Labels, components.PullRequestLabelsItem{Name: label})
+	}
+	server.Expect(&octo.PullsGetReq{
+		Owner:      "foo",
+		Repo:       "bar",
+		PullNumber: 12,
+	}, octotest.JSONResponder(200, body))
+}
+
+func testCheckConfig(t *testing.T) *conventionalpulls.Config {
+	t.Helper()
+	rule, err := conventionalpulls.GlobLabelRule("semver:*", conventionalpulls.VersionChangeMinor)
+	require.NoError(t, err)
+	return &conventionalpulls.Config{
+		LabelValues: map[string]conventionalpulls.VersionChange{
+			"breaking": conventionalpulls.VersionChangeMajor,
+			"bug|fix":  conventionalpulls.VersionChangePatch,
+		},
+		LabelRules: []*conventionalpulls.LabelRule{rule},
+	}
+}
+
+const testAcceptedLabels = `
+### Accepted labels
+
+| Label | Change |
+| --- | --- |
+| ` + "`breaking`" + ` | Major |
+| ` + "`bug\\|fix`" + ` | Patch |
+| glob matching ` + "`semver:*`" + ` | Minor |
+`
+
+type fakePRFetcher map[int]*conventionalpulls.PullRequest
+
+func (f fakePRFetcher) FetchPR(_ context.Context, id int) (*conventionalpulls.PullRequest, error) {
+	return f[id], nil
+}
+
+func TestLabelChecker_Check(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("labeled", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectPull(server, "docs", "Bug|Fix")
+		checker := NewLabelChecker(testCheckConfig(t), "foo", "bar", server.Client()...)
+		got, err := checker.Check(ctx, 12)
+		require.NoError(t, err)
+		require.Equal(t, 12, got.PullRequest)
+		require.Equal(t, testSHA, got.HeadSHA)
+		require.True(t, got.Passed)
+		require.NoError(t, got.Err)
+		require.Equal(t, "success", got.Conclusion())
+		require.Equal(t, "Labeled for a patch version change", got.Title)
+		require.Equal(t, "#12 is labeled for a patch version change.\n\n"+
+			"| Label | Matched rule | Change |\n| --- | --- | --- |\n"+
+			"| bug\\|fix | label `bug\\|fix` | Patch |\n"+
+			testAcceptedLabels, got.Summary)
+		require.Equal(t, conventionalpulls.VersionChangePatch, got.Report.VersionChange)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectPull(server, "docs")
+		checker := NewLabelChecker(testCheckConfig(t), "foo", "bar", server.Client()...)
+		got, err := checker.Check(ctx, 12)
+		require.NoError(t, err)
+		require.False(t, got.Passed)
+		require.Equal(t, "failure", got.Conclusion())
+		require.Equal(t, &conventionalpulls.PRMissingLabelErr{IDs: []int{12}}, got.Err)
+		require.Equal(t, "Missing a version label", got.Title)
+		require.Equal(t, "#12 needs one of the accepted labels before it is merged.\n"+testAcceptedLabels, got.Summary)
+	})
+
+	t.Run("conflicting", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectPull(server, "breaking", "semver:minor")
+		cfg := testCheckConfig(t)
+		cfg.StrictLabels = true
+		checker := NewLabelChecker(cfg, "foo", "bar", server.Client()...)
+		got, err := checker.Check(ctx, 12)
+		require.NoError(t, err)
+		require.False(t, got.Passed)
+		require.Equal(t, "Conflicting version labels", got.Title)
+		require.Equal(t, "#12 has labels for different version changes: breaking, semver:minor. "+
+			"Remove all but one of them.\n"+testAcceptedLabels, got.Summary)
+	})
+
+	t.Run("PRFetcher", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectPull(server, "docs")
+		checker := NewLabelChecker(testCheckConfig(t), "foo", "bar", server.Client()...)
+		checker.PRFetcher = fakePRFetcher{
+			12: {Number: 12, Labels: []string{"docs", "semver:feature"}},
+		}
+		got, err := checker.Check(ctx, 12)
+		require.NoError(t, err)
+		require.Equal(t, testSHA, got.HeadSHA)
+		require.True(t, got.Passed)
+		require.Equal(t, "Labeled for a minor version change", got.Title)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		server.Expect(&octo.PullsGetReq{
+			Owner:      "foo",
+			Repo:       "bar",
+			PullNumber: 12,
+		}, octotest.JSONResponder(http.StatusNotFound, map[string]string{"message": "Not Found"}))
+		checker := NewLabelChecker(testCheckConfig(t), "foo", "bar", server.Client()...)
+		got, err := checker.Check(ctx, 12)
+		require.Error(t, err)
+		require.Nil(t, got)
+	})
+}
+
+func TestLabelChecker_PostCheckRun(t *testing.T) {
+	server := octotest.New()
+	t.Cleanup(server.Finish)
+	server.Expect(&octo.ChecksCreateReq{
+		Owner: "foo",
+		Repo:  "bar",
+		RequestBody: octo.ChecksCreateReqBody{
+			HeadSha:    octo.String(testSHA),
+			Name:       octo.String("labels"),
+			Status:     octo.String("completed"),
+			Conclusion: octo.String("failure"),
+			DetailsUrl: octo.String("https://example.com/labels"),
+			Output: &octo.ChecksCreateReqBodyOutput{
+				Title:   octo.String("Missing a version label"),
+				Summary: octo.String("summary"),
+			},
+		},
+		AntiopePreview: true,
+	}, octotest.JSONResponder(201, &components.CheckRun{Id: 1}))
+	checker := NewLabelChecker(testCheckConfig(t), "foo", "bar", server.Client()...)
+	checker.Name = "labels"
+	checker.DetailsURL = "https://example.com/labels"
+	err := checker.PostCheckRun(context.Background(), &LabelCheck{
+		PullRequest: 12,
+		HeadSHA:     testSHA,
+		Title:       "Missing a version label",
+		Summary:     "summary",
+	})
+	require.NoError(t, err)
+}
+
+func TestLabelChecker_PostStatus(t *testing.T) {
+	server := octotest.New()
+	t.Cleanup(server.Finish)
+	server.Expect(&octo.ReposCreateCommitStatusReq{
+		Owner: "foo",
+		Repo:  "bar",
+		Sha:   testSHA,
+		RequestBody: octo.ReposCreateCommitStatusReqBody{
+			State:       octo.String("success"),
+			Context:     octo.String(DefaultCheckName),
+			Description: octo.String("Labeled for a minor version change"),
+		},
+	}, octotest.JSONResponder(201, &components.Status{Id: 1}))
+	checker := NewLabelChecker(testCheckConfig(t), "foo", "bar", server.Client()...)
+	err := checker.PostStatus(context.Background(), &LabelCheck{
+		PullRequest: 12,
+		HeadSHA:     testSHA,
+		Passed:      true,
+		Title:       "Labeled for a minor version change",
+		Summary:     "summary",
+	})
+	require.NoError(t, err)
+}
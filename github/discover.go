@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"sort"
+
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/octo-go"
+)
+
+type prDiscoverer struct {
+	client octo.Client
+	owner  string
+	repo   string
+}
+
+// NewPRDiscoverer returns a PRDiscoverer that uses GitHub's compare API to find the commits between two refs and
+// returns the merged pull requests whose merge commits are among them.
+func NewPRDiscoverer(owner, repo string, opt ...octo.RequestOption) conventionalpulls.PRDiscoverer {
+	return &prDiscoverer{
+		client: opt,
+		owner:  owner,
+		repo:   repo,
+	}
+}
+
+func (d *prDiscoverer) DiscoverPRs(ctx context.Context, baseRef, headRef string) ([]int, error) {
+	shas, err := d.compareCommits(ctx, baseRef, headRef)
+	if err != nil {
+		return nil, err
+	}
+	inRange := make(map[string]bool, len(shas))
+	for _, sha := range shas {
+		inRange[sha] = true
+	}
+	found := map[int]bool{}
+	for _, sha := range shas {
+		var pulls []int
+		pulls, err = d.mergedPulls(ctx, sha, inRange)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range pulls {
+			found[id] = true
+		}
+	}
+	prIDs := make([]int, 0, len(found))
+	for id := range found {
+		prIDs = append(prIDs, id)
+	}
+	sort.Ints(prIDs)
+	return prIDs, nil
+}
+
+// compareCommits returns the shas of all commits that are in headRef but not baseRef
+func (d *prDiscoverer) compareCommits(ctx context.Context, baseRef, headRef string) ([]string, error) {
+	var shas []string
+	req := &octo.ReposCompareCommitsReq{
+		Owner: d.owner,
+		Repo:  d.repo,
+		Base:  baseRef,
+		Head:  headRef,
+	}
+	for {
+		resp, err := d.client.ReposCompareCommits(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range resp.Data.Commits {
+			shas = append(shas, commit.Sha)
+		}
+		if !req.Rel(octo.RelNext, resp) {
+			return shas, nil
+		}
+	}
+}
+
+// mergedPulls returns the pull requests associated with sha that were merged by a commit in inRange
+func (d *prDiscoverer) mergedPulls(ctx context.Context, sha string, inRange map[string]bool) ([]int, error) {
+	var prIDs []int
+	req := &octo.ReposListPullRequestsAssociatedWithCommitReq{
+		Owner:        d.owner,
+		Repo:         d.repo,
+		CommitSha:    sha,
+		PerPage:      octo.Int64(100),
+		GrootPreview: true,
+	}
+	for {
+		resp, err := d.client.ReposListPullRequestsAssociatedWithCommit(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, pull := range *resp.Data {
+			if pull.MergedAt == "" || !inRange[pull.MergeCommitSha] {
+				continue
+			}
+			prIDs = append(prIDs, int(pull.Number))
+		}
+		if !req.Rel(octo.RelNext, resp) {
+			return prIDs, nil
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/octo-go"
+	"github.com/willabides/octo-go/components"
+	"github.com/willabides/octo-go/octotest"
+)
+
+// pageRequester is an octotest.HTTPRequester for a page of a request that has no Page field
+type pageRequester struct {
+	path string
+	page string
+}
+
+func (r *pageRequester) HTTPRequest(ctx context.Context, opt ...octo.RequestOption) (*http.Request, error) {
+	// borrow the base url from any request built with opt
+	req, err := (&octo.MetaGetReq{}).HTTPRequest(ctx, opt...)
+	if err != nil {
+		return nil, err
+	}
+	u := *req.URL
+	u.Path = r.path
+	u.RawQuery = "page=" + r.page
+	return http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+}
+
+func compareCommits(shas ...string) *octo.ReposCompareCommitsResponseBody {
+	body := &octo.ReposCompareCommitsResponseBody{}
+	for _, sha := range shas {
+		body.Commits = append(body.Commits, components.CommitComparisonCommitsItem{Sha: sha})
+	}
+	return body
+}
+
+func expectCommitPulls(server *octotest.Server, sha string, pulls ...components.PullRequestSimple) {
+	body := octo.ReposListPullRequestsAssociatedWithCommitResponseBody(pulls)
+	server.Expect(&octo.ReposListPullRequestsAssociatedWithCommitReq{
+		Owner:        "foo",
+		Repo:         "bar",
+		CommitSha:    sha,
+		PerPage:      octo.Int64(100),
+		GrootPreview: true,
+	}, octotest.JSONResponder(200, &body))
+}
+
+func mergedPull(number int64, mergeCommitSha string) components.PullRequestSimple {
+	return components.PullRequestSimple{
+		Number:         number,
+		MergedAt:       "2020-07-01T00:00:00Z",
+		MergeCommitSha: mergeCommitSha,
+	}
+}
+
+func TestNewPRDiscoverer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		compareReq := &octo.ReposCompareCommitsReq{
+			Owner: "foo",
+			Repo:  "bar",
+			Base:  "v1.0.0",
+			Head:  "main",
+		}
+		page2 := &pageRequester{path: "/repos/foo/bar/compare/v1.0.0...main", page: "2"}
+		server.Expect(page2, octotest.JSONResponder(200, compareCommits("c5")))
+		server.Expect(compareReq, octotest.RelLinkHandler(octo.RelNext,
+			octotest.JSONResponder(200, compareCommits("c1", "c2", "c3", "c4")), page2, server,
+		))
+		// squash merged
+		expectCommitPulls(server, "c1", mergedPull(1, "c1"))
+		// c2 is part of PR 2, which was merged by c3. It is also part of PR 4, which was merged elsewhere,
+		// and the still-open PR 5.
+		expectCommitPulls(server, "c2",
+			mergedPull(2, "c3"),
+			mergedPull(4, "abc"),
+			components.PullRequestSimple{Number: 5},
+		)
+		expectCommitPulls(server, "c3", mergedPull(2, "c3"))
+		// pushed directly
+		expectCommitPulls(server, "c4")
+		expectCommitPulls(server, "c5", mergedPull(3, "c5"))
+		discoverer := NewPRDiscoverer("foo", "bar", server.Client()...)
+		got, err := discoverer.DiscoverPRs(ctx, "v1.0.0", "main")
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2, 3}, got)
+	})
+
+	t.Run("no commits", func(t *testing.T) {
+		ctx := context.Background()
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		server.Expect(&octo.ReposCompareCommitsReq{
+			Owner: "foo",
+			Repo:  "bar",
+			Base:  "v1.0.0",
+			Head:  "main",
+		}, octotest.JSONResponder(200, compareCommits()))
+		discoverer := NewPRDiscoverer("foo", "bar", server.Client()...)
+		got, err := discoverer.DiscoverPRs(ctx, "v1.0.0", "main")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		ctx := context.Background()
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		server.Expect(&octo.ReposCompareCommitsReq{
+			Owner: "foo",
+			Repo:  "bar",
+			Base:  "v1.0.0",
+			Head:  "nope",
+		}, octotest.JSONResponder(http.StatusNotFound, "not found"))
+		discoverer := NewPRDiscoverer("foo", "bar", server.Client()...)
+		got, err := discoverer.DiscoverPRs(ctx, "v1.0.0", "nope")
+		require.Error(t, err)
+		require.Empty(t, got)
+	})
+}
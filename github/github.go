@@ -15,8 +15,12 @@ type prLabelFetcher struct {
 }
 
 func (f *prLabelFetcher) FetchPRLabels(id int) ([]string, error) {
+	return f.FetchPRLabelsContext(f.getCtx(), id)
+}
+
+func (f *prLabelFetcher) FetchPRLabelsContext(ctx context.Context, id int) ([]string, error) {
 	client := f.client
-	pull, err := client.PullsGet(f.getCtx(), &octo.PullsGetReq{
+	pull, err := client.PullsGet(ctx, &octo.PullsGetReq{
 		Owner:      f.owner,
 		Repo:       f.repo,
 		PullNumber: int64(id),
@@ -32,6 +36,8 @@ func (f *prLabelFetcher) FetchPRLabels(id int) ([]string, error) {
 }
 
 // NewPRLabelFetcher returns a PRLabelFetcher that queries GitHub for PR Labels
+//
+// The returned fetcher also implements conventionalpulls.ContextPRLabelFetcher. ctx is only used by FetchPRLabels.
 func NewPRLabelFetcher(ctx context.Context, owner, repo string, opt ...octo.RequestOption) conventionalpulls.PRLabelFetcher {
 	return &prLabelFetcher{
 		client: opt,
@@ -42,3 +48,41 @@ func NewPRLabelFetcher(ctx context.Context, owner, repo string, opt ...octo.Requ
 		},
 	}
 }
+
+type prFetcher struct {
+	client octo.Client
+	owner  string
+	repo   string
+}
+
+// NewPRFetcher returns a PRFetcher that queries GitHub for pull requests
+func NewPRFetcher(owner, repo string, opt ...octo.RequestOption) conventionalpulls.PRFetcher {
+	return &prFetcher{
+		client: opt,
+		owner:  owner,
+		repo:   repo,
+	}
+}
+
+func (f *prFetcher) FetchPR(ctx context.Context, id int) (*conventionalpulls.PullRequest, error) {
+	pull, err := f.client.PullsGet(ctx, &octo.PullsGetReq{
+		Owner:      f.owner,
+		Repo:       f.repo,
+		PullNumber: int64(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]string, len(pull.Data.Labels))
+	for i, label := range pull.Data.Labels {
+		labels[i] = label.Name
+	}
+	return &conventionalpulls.PullRequest{
+		Number: int(pull.Data.Number),
+		Title:  pull.Data.Title,
+		Body:   pull.Data.Body,
+		Author: pull.Data.User.Login,
+		URL:    pull.Data.HtmlUrl,
+		Labels: labels,
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
 	"github.com/willabides/octo-go"
 	"github.com/willabides/octo-go/components"
 	"github.com/willabides/octo-go/octotest"
@@ -75,4 +76,81 @@ func TestNewPRLabelFetcher(t *testing.T) {
 		require.Error(t, err)
 		require.Empty(t, got)
 	})
+
+	t.Run("FetchPRLabelsContext", func(t *testing.T) {
+		server := octotest.New()
+		owner := "foo"
+		repo := "bar"
+		id := 12
+		req := &octo.PullsGetReq{
+			Owner:      owner,
+			Repo:       repo,
+			PullNumber: int64(id),
+		}
+		respBody := &octo.PullsGetResponseBody{
+			Id: 12,
+			Labels: []components.PullRequestLabelsItem{
+				{Name: "label 1"},
+			},
+		}
+		server.Expect(req, octotest.JSONResponder(200, respBody))
+		canceledCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fetcher := NewPRLabelFetcher(canceledCtx, owner, repo, server.Client()...)
+		ctxFetcher, ok := fetcher.(conventionalpulls.ContextPRLabelFetcher)
+		require.True(t, ok)
+		got, err := ctxFetcher.FetchPRLabelsContext(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, []string{"label 1"}, got)
+		_, err = ctxFetcher.FetchPRLabelsContext(canceledCtx, id)
+		require.Error(t, err)
+	})
+}
+
+func TestNewPRFetcher(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+		server := octotest.New()
+		req := &octo.PullsGetReq{
+			Owner:      "foo",
+			Repo:       "bar",
+			PullNumber: 12,
+		}
+		respBody := &octo.PullsGetResponseBody{
+			Number:  12,
+			Title:   "add a thing",
+			Body:    "this adds a thing",
+			HtmlUrl: "https://github.com/foo/bar/pull/12",
+			User:    components.PullRequestUser{Login: "octocat"},
+			Labels: []components.PullRequestLabelsItem{
+				{Name: "label 1"},
+				{Name: "label 2"},
+			},
+		}
+		server.Expect(req, octotest.JSONResponder(200, respBody))
+		got, err := NewPRFetcher("foo", "bar", server.Client()...).FetchPR(ctx, 12)
+		require.NoError(t, err)
+		require.Equal(t, &conventionalpulls.PullRequest{
+			Number: 12,
+			Title:  "add a thing",
+			Body:   "this adds a thing",
+			Author: "octocat",
+			URL:    "https://github.com/foo/bar/pull/12",
+			Labels: []string{"label 1", "label 2"},
+		}, got)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx := context.Background()
+		server := octotest.New()
+		req := &octo.PullsGetReq{
+			Owner:      "foo",
+			Repo:       "bar",
+			PullNumber: 12,
+		}
+		server.Expect(req, octotest.JSONResponder(http.StatusNotFound, "not found"))
+		got, err := NewPRFetcher("foo", "bar", server.Client()...).FetchPR(ctx, 12)
+		require.Error(t, err)
+		require.Nil(t, got)
+	})
 }
@@ -0,0 +1,217 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/octo-go"
+)
+
+const (
+	defaultGraphQLURL = "https://api.github.com/graphql"
+
+	// graphQLBatchSize is the number of pull requests requested per query. Each pull request can have up to 100
+	// label nodes, so this keeps a query well under GitHub's node limit.
+	graphQLBatchSize = 50
+)
+
+// GraphQLOption is an option for NewGraphQLPRLabelFetcher
+type GraphQLOption func(f *graphQLFetcher)
+
+// WithGraphQLURL sets the GraphQL endpoint. Default is https://api.github.com/graphql
+func WithGraphQLURL(url string) GraphQLOption {
+	return func(f *graphQLFetcher) {
+		f.url = url
+	}
+}
+
+// WithGraphQLHTTPClient sets an http client to use for requests. If unset, http.DefaultClient is used
+func WithGraphQLHTTPClient(client *http.Client) GraphQLOption {
+	return func(f *graphQLFetcher) {
+		f.httpClient = client
+	}
+}
+
+// WithGraphQLToken authenticates requests with a personal access token
+func WithGraphQLToken(token string) GraphQLOption {
+	return WithGraphQLAuthProvider(tokenAuthProvider(token))
+}
+
+// WithGraphQLAuthProvider sets a provider to use in setting the Authorization header
+func WithGraphQLAuthProvider(authProvider octo.AuthProvider) GraphQLOption {
+	return func(f *graphQLFetcher) {
+		f.authProvider = authProvider
+	}
+}
+
+type tokenAuthProvider string
+
+func (t tokenAuthProvider) AuthorizationHeader(_ context.Context) (string, error) {
+	return "bearer " + string(t), nil
+}
+
+type graphQLFetcher struct {
+	url          string
+	httpClient   *http.Client
+	authProvider octo.AuthProvider
+	owner        string
+	repo         string
+}
+
+// NewGraphQLPRLabelFetcher returns a PRLabelFetcher that queries GitHub's GraphQL API for PR labels.
+//
+// The returned fetcher also implements conventionalpulls.BatchPRLabelFetcher, so Config fetches labels for up to
+// 50 PRs per request. Only the first 100 labels of each PR are fetched.
+func NewGraphQLPRLabelFetcher(owner, repo string, opt ...GraphQLOption) conventionalpulls.PRLabelFetcher {
+	f := &graphQLFetcher{
+		url:        defaultGraphQLURL,
+		httpClient: http.DefaultClient,
+		owner:      owner,
+		repo:       repo,
+	}
+	for _, o := range opt {
+		o(f)
+	}
+	return f
+}
+
+func (f *graphQLFetcher) FetchPRLabels(id int) ([]string, error) {
+	return f.FetchPRLabelsContext(context.Background(), id)
+}
+
+func (f *graphQLFetcher) FetchPRLabelsContext(ctx context.Context, id int) ([]string, error) {
+	labels, err := f.FetchPRLabelsBatch(ctx, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	return labels[id], nil
+}
+
+func (f *graphQLFetcher) FetchPRLabelsBatch(ctx context.Context, ids []int) (map[int][]string, error) {
+	ids = uniqueInts(ids)
+	result := make(map[int][]string, len(ids))
+	for len(ids) > 0 {
+		batch := ids
+		if len(batch) > graphQLBatchSize {
+			batch = batch[:graphQLBatchSize]
+		}
+		ids = ids[len(batch):]
+		err := f.fetchBatch(ctx, batch, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data struct {
+		Repository map[string]*struct {
+			Labels struct {
+				Nodes []struct {
+					Name string `json:"name"`
+				} `json:"nodes"`
+			} `json:"labels"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func labelsQuery(ids []int) string {
+	var query strings.Builder
+	query.WriteString("query($owner: String!, $repo: String!) {\n  repository(owner: $owner, name: $repo) {\n")
+	for _, id := range ids {
+		fmt.Fprintf(&query, "    pr%d: pullRequest(number: %d) { labels(first: 100) { nodes { name } } }\n", id, id)
+	}
+	query.WriteString("  }\n}\n")
+	return query.String()
+}
+
+func (f *graphQLFetcher) fetchBatch(ctx context.Context, ids []int, result map[int][]string) error {
+	var resp graphQLResponse
+	err := f.do(ctx, &graphQLRequest{
+		Query: labelsQuery(ids),
+		Variables: map[string]interface{}{
+			"owner": f.owner,
+			"repo":  f.repo,
+		},
+	}, &resp)
+	if err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			msgs[i] = e.Message
+		}
+		return fmt.Errorf("graphql errors: %s", strings.Join(msgs, "; "))
+	}
+	for _, id := range ids {
+		pull := resp.Data.Repository[fmt.Sprintf("pr%d", id)]
+		if pull == nil {
+			return fmt.Errorf("pull request %d not found", id)
+		}
+		labels := make([]string, len(pull.Labels.Nodes))
+		for i, node := range pull.Labels.Nodes {
+			labels[i] = node.Name
+		}
+		result[id] = labels
+	}
+	return nil
+}
+
+func (f *graphQLFetcher) do(ctx context.Context, gqlReq *graphQLRequest, target interface{}) error {
+	body, err := json.Marshal(gqlReq)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.authProvider != nil {
+		var authHeader string
+		authHeader, err = f.authProvider.AuthorizationHeader(ctx)
+		if err != nil {
+			return fmt.Errorf("error setting authorization header: %v", err)
+		}
+		req.Header.Set("Authorization", authHeader)
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck // nothing to do with this error
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("graphql request returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func uniqueInts(ints []int) []int {
+	seen := make(map[int]bool, len(ints))
+	result := make([]int, 0, len(ints))
+	for _, i := range ints {
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+		result = append(result, i)
+	}
+	sort.Ints(result)
+	return result
+}
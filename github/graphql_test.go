@@ -0,0 +1,161 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+)
+
+var pullRequestAliasExp = regexp.MustCompile(`pr(\d+): pullRequest\(number: (\d+)\)`)
+
+// graphQLServer is a stand-in for GitHub's GraphQL API that serves labels from pulls
+type graphQLServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	pulls    map[int][]string
+	requests []graphQLRequest
+}
+
+func newGraphQLServer(t *testing.T, pulls map[int][]string) *graphQLServer {
+	t.Helper()
+	s := &graphQLServer{pulls: pulls}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "bearer mytoken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req graphQLRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.mu.Unlock()
+		repository := map[string]interface{}{}
+		var errs []map[string]interface{}
+		for _, match := range pullRequestAliasExp.FindAllStringSubmatch(req.Query, -1) {
+			id, err := strconv.Atoi(match[2])
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			labels, ok := s.pulls[id]
+			if !ok {
+				repository["pr"+match[1]] = nil
+				errs = append(errs, map[string]interface{}{
+					"type":    "NOT_FOUND",
+					"message": fmt.Sprintf("Could not resolve to a PullRequest with the number of %d.", id),
+				})
+				continue
+			}
+			nodes := make([]map[string]string, len(labels))
+			for i, label := range labels {
+				nodes[i] = map[string]string{"name": label}
+			}
+			repository["pr"+match[1]] = map[string]interface{}{
+				"labels": map[string]interface{}{"nodes": nodes},
+			}
+		}
+		resp := map[string]interface{}{
+			"data": map[string]interface{}{"repository": repository},
+		}
+		if len(errs) > 0 {
+			resp["errors"] = errs
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp) //nolint:errcheck // test server
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *graphQLServer) fetcher(opt ...GraphQLOption) conventionalpulls.PRLabelFetcher {
+	opt = append([]GraphQLOption{
+		WithGraphQLURL(s.URL + "/graphql"),
+		WithGraphQLHTTPClient(s.Client()),
+		WithGraphQLToken("mytoken"),
+	}, opt...)
+	return NewGraphQLPRLabelFetcher("foo", "bar", opt...)
+}
+
+func TestNewGraphQLPRLabelFetcher(t *testing.T) {
+	t.Run("FetchPRLabels", func(t *testing.T) {
+		server := newGraphQLServer(t, map[int][]string{
+			12: {"label 1", "label 2"},
+			13: {},
+		})
+		fetcher := server.fetcher()
+		got, err := fetcher.FetchPRLabels(12)
+		require.NoError(t, err)
+		require.Equal(t, []string{"label 1", "label 2"}, got)
+		got, err = fetcher.FetchPRLabels(13)
+		require.NoError(t, err)
+		require.Empty(t, got)
+		require.Len(t, server.requests, 2)
+		require.Equal(t, map[string]interface{}{"owner": "foo", "repo": "bar"}, server.requests[0].Variables)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := newGraphQLServer(t, map[int][]string{})
+		got, err := server.fetcher().FetchPRLabels(12)
+		require.EqualError(t, err, "graphql errors: Could not resolve to a PullRequest with the number of 12.")
+		require.Nil(t, got)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		server := newGraphQLServer(t, map[int][]string{12: {"label 1"}})
+		got, err := server.fetcher(WithGraphQLToken("wrong")).FetchPRLabels(12)
+		require.EqualError(t, err, "graphql request returned status 401")
+		require.Nil(t, got)
+	})
+
+	t.Run("batches", func(t *testing.T) {
+		pulls := map[int][]string{}
+		ids := make([]int, 0, 120)
+		for id := 1; id <= 120; id++ {
+			pulls[id] = []string{fmt.Sprintf("label %d", id)}
+			ids = append(ids, id)
+		}
+		server := newGraphQLServer(t, pulls)
+		batchFetcher, ok := server.fetcher().(conventionalpulls.BatchPRLabelFetcher)
+		require.True(t, ok)
+		got, err := batchFetcher.FetchPRLabelsBatch(context.Background(), append(ids, 1, 2, 3))
+		require.NoError(t, err)
+		require.Equal(t, pulls, got)
+		require.Len(t, server.requests, 3)
+		require.Len(t, pullRequestAliasExp.FindAllString(server.requests[0].Query, -1), 50)
+		require.Len(t, pullRequestAliasExp.FindAllString(server.requests[1].Query, -1), 50)
+		require.Len(t, pullRequestAliasExp.FindAllString(server.requests[2].Query, -1), 20)
+	})
+
+	t.Run("with Config", func(t *testing.T) {
+		server := newGraphQLServer(t, map[int][]string{
+			1: {"Patch"},
+			2: {"Minor Change"},
+			3: {"Non-Production Change"},
+		})
+		cfg := &conventionalpulls.Config{
+			PRLabelFetcher: server.fetcher(),
+			RequireLabels:  true,
+		}
+		got, err := cfg.NextVersion("v1.2.3", 1, 2, 3)
+		require.NoError(t, err)
+		require.Equal(t, "v1.3.0", got)
+		require.Len(t, server.requests, 1)
+	})
+}
@@ -0,0 +1,229 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/willabides/octo-go"
+)
+
+// Release is a GitHub release for ReleasePublisher to create
+type Release struct {
+	// Tag is the name of the tag to create such as "v1.2.3"
+	Tag string
+
+	// SHA is the commit to tag
+	SHA string
+
+	// Name is the release's title. Default is Tag.
+	Name string
+
+	// Notes is the release's body. changelog.Generator generates notes from the release's pull requests.
+	Notes string
+
+	// TagMessage is the annotated tag's message. Default is Name.
+	TagMessage string
+
+	Draft      bool
+	Prerelease bool
+}
+
+// PublishedRelease is a release on GitHub
+type PublishedRelease struct {
+	ID         int64
+	Tag        string
+	URL        string
+	Draft      bool
+	Prerelease bool
+
+	// Existing is true when a release for the tag already existed and nothing was created
+	Existing bool
+}
+
+// ReleasePublisher creates tags and releases on GitHub
+type ReleasePublisher struct {
+	client octo.Client
+	owner  string
+	repo   string
+}
+
+// NewReleasePublisher returns a ReleasePublisher for a GitHub repository. The token needs permission to write the
+// repository's contents.
+func NewReleasePublisher(owner, repo string, opt ...octo.RequestOption) *ReleasePublisher {
+	return &ReleasePublisher{
+		client: opt,
+		owner:  owner,
+		repo:   repo,
+	}
+}
+
+// Publish creates an annotated tag for release.SHA and a release for the tag. It is safe to run again after a
+// failure or a successful publish. When a release for the tag exists, it is returned with Existing set and nothing is
+// created. An existing tag is reused when it points to release.SHA. When it points somewhere else, Publish returns a
+// *TagConflictErr.
+func (p *ReleasePublisher) Publish(ctx context.Context, release *Release) (*PublishedRelease, error) {
+	if release.Tag == "" || release.SHA == "" {
+		return nil, fmt.Errorf("release Tag and SHA are required")
+	}
+	existing, err := p.findRelease(ctx, release.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	err = p.ensureTag(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+	name := release.Name
+	if name == "" {
+		name = release.Tag
+	}
+	resp, err := p.client.ReposCreateRelease(ctx, &octo.ReposCreateReleaseReq{
+		Owner: p.owner,
+		Repo:  p.repo,
+		RequestBody: octo.ReposCreateReleaseReqBody{
+			TagName:         octo.String(release.Tag),
+			TargetCommitish: octo.String(release.SHA),
+			Name:            octo.String(name),
+			Body:            octo.String(release.Notes),
+			Draft:           octo.Bool(release.Draft),
+			Prerelease:      octo.Bool(release.Prerelease),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PublishedRelease{
+		ID:         resp.Data.Id,
+		Tag:        resp.Data.TagName,
+		URL:        resp.Data.HtmlUrl,
+		Draft:      resp.Data.Draft,
+		Prerelease: resp.Data.Prerelease,
+	}, nil
+}
+
+// findRelease returns the release for tag or nil when there isn't one. Releases are listed instead of fetched by tag
+// because draft releases can't be fetched by tag.
+func (p *ReleasePublisher) findRelease(ctx context.Context, tag string) (*PublishedRelease, error) {
+	req := &octo.ReposListReleasesReq{
+		Owner:   p.owner,
+		Repo:    p.repo,
+		PerPage: octo.Int64(100),
+	}
+	for {
+		resp, err := p.client.ReposListReleases(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, release := range *resp.Data {
+			if release.TagName != tag {
+				continue
+			}
+			return &PublishedRelease{
+				ID:         release.Id,
+				Tag:        release.TagName,
+				URL:        release.HtmlUrl,
+				Draft:      release.Draft,
+				Prerelease: release.Prerelease,
+				Existing:   true,
+			}, nil
+		}
+		if !req.Rel(octo.RelNext, resp) {
+			return nil, nil
+		}
+	}
+}
+
+// ensureTag creates an annotated tag for release unless the tag already exists for release.SHA
+func (p *ReleasePublisher) ensureTag(ctx context.Context, release *Release) error {
+	sha, err := p.tagCommit(ctx, release.Tag)
+	if err != nil {
+		return err
+	}
+	if sha == release.SHA {
+		return nil
+	}
+	if sha != "" {
+		return &TagConflictErr{
+			Tag:         release.Tag,
+			SHA:         release.SHA,
+			ExistingSHA: sha,
+		}
+	}
+	message := release.TagMessage
+	if message == "" {
+		message = release.Name
+	}
+	if message == "" {
+		message = release.Tag
+	}
+	tag, err := p.client.GitCreateTag(ctx, &octo.GitCreateTagReq{
+		Owner: p.owner,
+		Repo:  p.repo,
+		RequestBody: octo.GitCreateTagReqBody{
+			Tag:     octo.String(release.Tag),
+			Message: octo.String(message),
+			Object:  octo.String(release.SHA),
+			Type:    octo.String("commit"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.client.GitCreateRef(ctx, &octo.GitCreateRefReq{
+		Owner: p.owner,
+		Repo:  p.repo,
+		RequestBody: octo.GitCreateRefReqBody{
+			Ref: octo.String("refs/tags/" + release.Tag),
+			Sha: octo.String(tag.Data.Sha),
+		},
+	})
+	return err
+}
+
+// tagCommit returns the sha of the commit that tag points to or "" when the tag doesn't exist
+func (p *ReleasePublisher) tagCommit(ctx context.Context, tag string) (string, error) {
+	ref, err := p.client.GitGetRef(ctx, &octo.GitGetRefReq{
+		Owner: p.owner,
+		Repo:  p.repo,
+		Ref:   "tags/" + tag,
+	})
+	if isNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if ref.Data.Object.Type != "tag" {
+		return ref.Data.Object.Sha, nil
+	}
+	annotated, err := p.client.GitGetTag(ctx, &octo.GitGetTagReq{
+		Owner:  p.owner,
+		Repo:   p.repo,
+		TagSha: ref.Data.Object.Sha,
+	})
+	if err != nil {
+		return "", err
+	}
+	return annotated.Data.Object.Sha, nil
+}
+
+func isNotFound(err error) bool {
+	var clientErr *octo.ClientError
+	return errors.As(err, &clientErr) && clientErr.HTTPResponse().StatusCode == http.StatusNotFound
+}
+
+// TagConflictErr is an error indicating a release's tag already exists for a different commit
+type TagConflictErr struct {
+	Tag         string
+	SHA         string
+	ExistingSHA string
+}
+
+func (e *TagConflictErr) Error() string {
+	return fmt.Sprintf("tag %s already exists for commit %s", e.Tag, e.ExistingSHA)
+}
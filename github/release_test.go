@@ -0,0 +1,175 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/octo-go"
+	"github.com/willabides/octo-go/components"
+	"github.com/willabides/octo-go/octotest"
+)
+
+const testSHA = "0123456789abcdef0123456789abcdef01234567"
+
+func expectReleases(server *octotest.Server, releases ...components.Release2) {
+	server.Expect(&octo.ReposListReleasesReq{
+		Owner:   "foo",
+		Repo:    "bar",
+		PerPage: octo.Int64(100),
+	}, octotest.JSONResponder(200, releases))
+}
+
+func expectTagRef(server *octotest.Server, ref interface{}) {
+	status := 200
+	if ref == nil {
+		status, ref = 404, map[string]string{"message": "Not Found"}
+	}
+	server.Expect(&octo.GitGetRefReq{
+		Owner: "foo",
+		Repo:  "bar",
+		Ref:   "tags/v1.2.0",
+	}, octotest.JSONResponder(status, ref))
+}
+
+func expectCreateRelease(server *octotest.Server, name, notes string, draft, prerelease bool) {
+	server.Expect(&octo.ReposCreateReleaseReq{
+		Owner: "foo",
+		Repo:  "bar",
+		RequestBody: octo.ReposCreateReleaseReqBody{
+			TagName:         octo.String("v1.2.0"),
+			TargetCommitish: octo.String(testSHA),
+			Name:            octo.String(name),
+			Body:            octo.String(notes),
+			Draft:           octo.Bool(draft),
+			Prerelease:      octo.Bool(prerelease),
+		},
+	}, octotest.JSONResponder(201, &components.Release{
+		Id:         42,
+		TagName:    "v1.2.0",
+		HtmlUrl:    "https://github.com/foo/bar/releases/tag/v1.2.0",
+		Draft:      draft,
+		Prerelease: prerelease,
+	}))
+}
+
+func TestReleasePublisher_Publish(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("new tag", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectReleases(server, components.Release2{TagName: "v1.1.0"})
+		expectTagRef(server, nil)
+		server.Expect(&octo.GitCreateTagReq{
+			Owner: "foo",
+			Repo:  "bar",
+			RequestBody: octo.GitCreateTagReqBody{
+				Tag:     octo.String("v1.2.0"),
+				Message: octo.String("Widgets"),
+				Object:  octo.String(testSHA),
+				Type:    octo.String("commit"),
+			},
+		}, octotest.JSONResponder(201, &components.GitTag{Sha: "tagsha"}))
+		server.Expect(&octo.GitCreateRefReq{
+			Owner: "foo",
+			Repo:  "bar",
+			RequestBody: octo.GitCreateRefReqBody{
+				Ref: octo.String("refs/tags/v1.2.0"),
+				Sha: octo.String("tagsha"),
+			},
+		}, octotest.JSONResponder(201, &components.GitRef{Ref: "refs/tags/v1.2.0"}))
+		expectCreateRelease(server, "Widgets", "## v1.2.0\n", true, false)
+
+		publisher := NewReleasePublisher("foo", "bar", server.Client()...)
+		got, err := publisher.Publish(ctx, &Release{
+			Tag:   "v1.2.0",
+			SHA:   testSHA,
+			Name:  "Widgets",
+			Notes: "## v1.2.0\n",
+			Draft: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &PublishedRelease{
+			ID:    42,
+			Tag:   "v1.2.0",
+			URL:   "https://github.com/foo/bar/releases/tag/v1.2.0",
+			Draft: true,
+		}, got)
+	})
+
+	t.Run("existing release", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectReleases(server, components.Release2{
+			Id:      41,
+			TagName: "v1.2.0",
+			HtmlUrl: "https://github.com/foo/bar/releases/tag/v1.2.0",
+			Draft:   true,
+		})
+		publisher := NewReleasePublisher("foo", "bar", server.Client()...)
+		got, err := publisher.Publish(ctx, &Release{Tag: "v1.2.0", SHA: testSHA})
+		require.NoError(t, err)
+		require.Equal(t, &PublishedRelease{
+			ID:       41,
+			Tag:      "v1.2.0",
+			URL:      "https://github.com/foo/bar/releases/tag/v1.2.0",
+			Draft:    true,
+			Existing: true,
+		}, got)
+	})
+
+	t.Run("existing annotated tag", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectReleases(server)
+		expectTagRef(server, &components.GitRef{
+			Ref:    "refs/tags/v1.2.0",
+			Object: components.GitRefObject{Sha: "tagsha", Type: "tag"},
+		})
+		server.Expect(&octo.GitGetTagReq{
+			Owner:  "foo",
+			Repo:   "bar",
+			TagSha: "tagsha",
+		}, octotest.JSONResponder(200, &components.GitTag{
+			Sha:    "tagsha",
+			Object: components.GitTagObject{Sha: testSHA, Type: "commit"},
+		}))
+		expectCreateRelease(server, "v1.2.0", "", false, true)
+
+		publisher := NewReleasePublisher("foo", "bar", server.Client()...)
+		got, err := publisher.Publish(ctx, &Release{Tag: "v1.2.0", SHA: testSHA, Prerelease: true})
+		require.NoError(t, err)
+		require.Equal(t, int64(42), got.ID)
+		require.True(t, got.Prerelease)
+		require.False(t, got.Existing)
+	})
+
+	t.Run("tag conflict", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectReleases(server)
+		expectTagRef(server, &components.GitRef{
+			Ref:    "refs/tags/v1.2.0",
+			Object: components.GitRefObject{Sha: "othersha", Type: "commit"},
+		})
+		publisher := NewReleasePublisher("foo", "bar", server.Client()...)
+		got, err := publisher.Publish(ctx, &Release{Tag: "v1.2.0", SHA: testSHA})
+		require.EqualError(t, err, "tag v1.2.0 already exists for commit othersha")
+		require.Equal(t, &TagConflictErr{Tag: "v1.2.0", SHA: testSHA, ExistingSHA: "othersha"}, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		publisher := NewReleasePublisher("foo", "bar", server.Client()...)
+		_, err := publisher.Publish(ctx, &Release{Tag: "v1.2.0", SHA: testSHA})
+		require.Error(t, err)
+	})
+
+	t.Run("missing SHA", func(t *testing.T) {
+		_, err := NewReleasePublisher("foo", "bar").Publish(ctx, &Release{Tag: "v1.2.0"})
+		require.EqualError(t, err, "release Tag and SHA are required")
+	})
+}
@@ -0,0 +1,198 @@
+package github
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/willabides/octo-go"
+)
+
+// RetryPolicy configures retries of GitHub requests that fail with a transient error. Transient errors are network
+// errors, 5xx responses, 429 responses and rate limited 403 responses. Other responses such as 401 and 404 are
+// permanent and are returned without retrying.
+//
+// GitHub may have processed a request before failing with a network error or 5xx response, so only GET and HEAD
+// requests are retried after those errors. Other requests are only retried when they are rate limited.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a request is retried. Zero means requests aren't retried.
+	MaxRetries int
+
+	// MinBackoff is the wait before the first retry when the response doesn't say how long to wait. It doubles with
+	// each retry and a random jitter of up to half the wait is subtracted. Default is one second.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the longest wait between retries when the response doesn't say how long to wait. Default is 30
+	// seconds.
+	MaxBackoff time.Duration
+
+	// MaxWait is the longest wait for a Retry-After or X-RateLimit-Reset header. When a response asks for a longer
+	// wait, it is returned without retrying. Default is five minutes.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy retries requests up to three times with the default waits
+var DefaultRetryPolicy = &RetryPolicy{MaxRetries: 3}
+
+// WithRetries sets an http client that retries requests according to policy. It replaces any client set with
+// octo.WithHTTPClient. Use policy.Transport to add retries to another client.
+func WithRetries(policy *RetryPolicy) octo.RequestOption {
+	return octo.WithHTTPClient(&http.Client{
+		Transport: policy.Transport(nil),
+	})
+}
+
+// Transport returns an http.RoundTripper that retries requests sent with base. A nil base is
+// http.DefaultTransport. Requests with a body are only retried when their GetBody is set.
+func (p *RetryPolicy) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{
+		policy: p,
+		base:   base,
+		now:    time.Now,
+		sleep:  sleepContext,
+		jitter: func(d time.Duration) time.Duration {
+			return time.Duration(rand.Int63n(int64(d/2) + 1)) //nolint:gosec // jitter doesn't need crypto/rand
+		},
+	}
+}
+
+func (p *RetryPolicy) minBackoff() time.Duration {
+	if p.MinBackoff > 0 {
+		return p.MinBackoff
+	}
+	return time.Second
+}
+
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff > 0 {
+		return p.MaxBackoff
+	}
+	return 30 * time.Second
+}
+
+func (p *RetryPolicy) maxWait() time.Duration {
+	if p.MaxWait > 0 {
+		return p.MaxWait
+	}
+	return 5 * time.Minute
+}
+
+type retryTransport struct {
+	policy *RetryPolicy
+	base   http.RoundTripper
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(d time.Duration) time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.policy.MaxRetries || !retryable(req, resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+		wait, ok := t.wait(resp, attempt)
+		if !ok {
+			return resp, err
+		}
+		retryReq, ok := rewind(req)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			// drain the body so the connection can be reused
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		err = t.sleep(req.Context(), wait)
+		if err != nil {
+			return nil, err
+		}
+		req = retryReq
+	}
+}
+
+// wait returns how long to wait before retrying resp. Returns false when the response asks for a wait longer than
+// MaxWait.
+func (t *retryTransport) wait(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			wait := time.Duration(seconds) * time.Second
+			return wait, wait <= t.policy.maxWait()
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+				wait := time.Unix(reset, 0).Sub(t.now())
+				if wait < 0 {
+					wait = 0
+				}
+				return wait, wait <= t.policy.maxWait()
+			}
+		}
+	}
+	backoff := t.policy.minBackoff()
+	for i := 0; i < attempt && backoff < t.policy.maxBackoff(); i++ {
+		backoff *= 2
+	}
+	if backoff > t.policy.maxBackoff() {
+		backoff = t.policy.maxBackoff()
+	}
+	return backoff - t.jitter(backoff), true
+}
+
+// retryable returns true when req got resp and err and might succeed if it is sent again. Requests that aren't
+// idempotent are only retryable when they were rate limited, because a rate limited request wasn't processed.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return err != nil || resp.StatusCode >= 500 || rateLimited(resp)
+	}
+	return err == nil && rateLimited(resp)
+}
+
+// rateLimited returns true when resp is a rate limit response
+func rateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		// primary and secondary rate limits are 403s. Other 403s are permanent.
+		return resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0"
+	default:
+		return false
+	}
+}
+
+// rewind returns a copy of req that can be sent again. Returns false when req's body can't be rewound.
+func rewind(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retryReq := req.Clone(req.Context())
+	retryReq.Body = body
+	return retryReq, true
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,274 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/octo-go"
+)
+
+type scriptedResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+// scriptedServer responds to each request with the next response in script. Requests after the end of the script
+// fail the test.
+func scriptedServer(t *testing.T, script ...scriptedResponse) (server *httptest.Server, requests func() int) {
+	t.Helper()
+	var mu sync.Mutex
+	count := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		if count > len(script) {
+			http.Error(w, "unexpected request", http.StatusTeapot)
+			return
+		}
+		resp := script[count-1]
+		for k, v := range resp.headers {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.status)
+		_, _ = w.Write([]byte(resp.body))
+	}))
+	// cleanups run last-in first-out, so the server is closed before checking for unexpected requests
+	t.Cleanup(func() {
+		require.LessOrEqual(t, count, len(script), "unexpected request")
+	})
+	t.Cleanup(server.Close)
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return count
+	}
+}
+
+// testRetryTransport returns a retryTransport that records sleeps instead of sleeping
+func testRetryTransport(policy *RetryPolicy, now time.Time) (*retryTransport, *[]time.Duration) {
+	var sleeps []time.Duration
+	tr := policy.Transport(nil).(*retryTransport)
+	tr.now = func() time.Time { return now }
+	tr.jitter = func(d time.Duration) time.Duration { return d / 4 }
+	tr.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return tr, &sleeps
+}
+
+func TestRetryPolicy_Transport(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	ok := scriptedResponse{status: 200, body: `{"number": 12, "labels": [{"name": "label 1"}]}`}
+
+	for _, td := range []struct {
+		name       string
+		policy     *RetryPolicy
+		script     []scriptedResponse
+		wantStatus int
+		wantSleeps []time.Duration
+	}{
+		{
+			name:   "exponential backoff",
+			policy: &RetryPolicy{MaxRetries: 3},
+			script: []scriptedResponse{
+				{status: 502}, {status: 503}, {status: 500}, ok,
+			},
+			wantStatus: 200,
+			wantSleeps: []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond, 3 * time.Second},
+		},
+		{
+			name:   "max backoff",
+			policy: &RetryPolicy{MaxRetries: 3, MinBackoff: 4 * time.Second, MaxBackoff: 10 * time.Second},
+			script: []scriptedResponse{
+				{status: 502}, {status: 502}, {status: 502}, ok,
+			},
+			wantStatus: 200,
+			wantSleeps: []time.Duration{3 * time.Second, 6 * time.Second, 7500 * time.Millisecond},
+		},
+		{
+			name:       "retries exhausted",
+			policy:     &RetryPolicy{MaxRetries: 2},
+			script:     []scriptedResponse{{status: 502}, {status: 502}, {status: 504}},
+			wantStatus: 504,
+			wantSleeps: []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond},
+		},
+		{
+			name:       "no retries",
+			policy:     &RetryPolicy{},
+			script:     []scriptedResponse{{status: 502}},
+			wantStatus: 502,
+		},
+		{
+			name:   "Retry-After",
+			policy: DefaultRetryPolicy,
+			script: []scriptedResponse{
+				{status: 403, headers: map[string]string{"Retry-After": "60"}, body: `{"message": "You have exceeded a secondary rate limit."}`},
+				{status: 429, headers: map[string]string{"Retry-After": "0"}},
+				ok,
+			},
+			wantStatus: 200,
+			wantSleeps: []time.Duration{time.Minute, 0},
+		},
+		{
+			name:   "X-RateLimit-Reset",
+			policy: DefaultRetryPolicy,
+			script: []scriptedResponse{
+				{status: 403, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1600000090"}},
+				{status: 403, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1599999999"}},
+				ok,
+			},
+			wantStatus: 200,
+			wantSleeps: []time.Duration{90 * time.Second, 0},
+		},
+		{
+			name:   "wait longer than MaxWait",
+			policy: &RetryPolicy{MaxRetries: 3, MaxWait: time.Minute},
+			script: []scriptedResponse{
+				{status: 403, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1600003600"}},
+			},
+			wantStatus: 403,
+		},
+		{
+			name:       "not found",
+			policy:     DefaultRetryPolicy,
+			script:     []scriptedResponse{{status: 404, body: `{"message": "Not Found"}`}},
+			wantStatus: 404,
+		},
+		{
+			name:       "unauthorized",
+			policy:     DefaultRetryPolicy,
+			script:     []scriptedResponse{{status: 401, body: `{"message": "Bad credentials"}`}},
+			wantStatus: 401,
+		},
+		{
+			name:       "forbidden",
+			policy:     DefaultRetryPolicy,
+			script:     []scriptedResponse{{status: 403, headers: map[string]string{"X-RateLimit-Remaining": "4999"}}},
+			wantStatus: 403,
+		},
+	} {
+		td := td
+		t.Run(td.name, func(t *testing.T) {
+			server, requests := scriptedServer(t, td.script...)
+			tr, sleeps := testRetryTransport(td.policy, now)
+			client := &http.Client{Transport: tr}
+			resp, err := client.Get(server.URL)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, td.wantStatus, resp.StatusCode)
+			require.Equal(t, td.wantSleeps, *sleeps)
+			require.Equal(t, len(td.script), requests())
+		})
+	}
+
+	t.Run("POST server error", func(t *testing.T) {
+		server, requests := scriptedServer(t, scriptedResponse{status: 502})
+		tr, sleeps := testRetryTransport(DefaultRetryPolicy, now)
+		resp, err := (&http.Client{Transport: tr}).Post(server.URL, "application/json", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, 502, resp.StatusCode)
+		require.Empty(t, *sleeps)
+		require.Equal(t, 1, requests())
+	})
+
+	t.Run("POST network error", func(t *testing.T) {
+		tr, sleeps := testRetryTransport(DefaultRetryPolicy, now)
+		tr.base = roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("connection reset")
+		})
+		_, err := (&http.Client{Transport: tr}).Post("http://example.com", "application/json", strings.NewReader(`{}`))
+		require.Error(t, err)
+		require.Empty(t, *sleeps)
+	})
+
+	t.Run("POST rate limited", func(t *testing.T) {
+		var mu sync.Mutex
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body) //nolint:errcheck // an error shows up as a missing body
+			mu.Lock()
+			defer mu.Unlock()
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusForbidden)
+			}
+		}))
+		t.Cleanup(server.Close)
+		tr, sleeps := testRetryTransport(DefaultRetryPolicy, now)
+		resp, err := (&http.Client{Transport: tr}).Post(server.URL, "text/plain", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, 200, resp.StatusCode)
+		require.Equal(t, []time.Duration{time.Second}, *sleeps)
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, []string{"foo", "foo"}, bodies)
+	})
+
+	t.Run("network error", func(t *testing.T) {
+		tr, sleeps := testRetryTransport(&RetryPolicy{MaxRetries: 2}, now)
+		tr.base = roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("connection reset")
+		})
+		_, err := (&http.Client{Transport: tr}).Get("http://example.com")
+		require.Error(t, err)
+		require.Len(t, *sleeps, 2)
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		server, requests := scriptedServer(t, scriptedResponse{status: 502}, ok)
+		tr := DefaultRetryPolicy.Transport(nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err = (&http.Client{Transport: tr}).Do(req)
+		require.True(t, errors.Is(err, context.Canceled))
+		require.Equal(t, 1, requests())
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewPRLabelFetcher_retries(t *testing.T) {
+	server, requests := scriptedServer(t,
+		scriptedResponse{status: 502, body: `{"message": "Server Error"}`},
+		scriptedResponse{status: 403, headers: map[string]string{"Retry-After": "1"}, body: `{"message": "secondary rate limit"}`},
+		scriptedResponse{status: 200, body: `{"number": 12, "labels": [{"name": "label 1"}]}`},
+		scriptedResponse{status: 404, body: `{"message": "Not Found"}`},
+	)
+	baseURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	tr, sleeps := testRetryTransport(DefaultRetryPolicy, time.Now())
+	fetcher := NewPRLabelFetcher(context.Background(), "foo", "bar",
+		octo.WithBaseURL(*baseURL),
+		octo.WithHTTPClient(&http.Client{Transport: tr}),
+	)
+	got, err := fetcher.FetchPRLabels(12)
+	require.NoError(t, err)
+	require.Equal(t, []string{"label 1"}, got)
+	require.Equal(t, []time.Duration{750 * time.Millisecond, time.Second}, *sleeps)
+
+	_, err = fetcher.FetchPRLabels(13)
+	require.Error(t, err)
+	require.Equal(t, 4, requests())
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/octo-go"
+)
+
+type tagLister struct {
+	client octo.Client
+	owner  string
+	repo   string
+}
+
+// NewTagLister returns a TagLister that lists a repository's tags from GitHub
+func NewTagLister(owner, repo string, opt ...octo.RequestOption) conventionalpulls.TagLister {
+	return &tagLister{
+		client: opt,
+		owner:  owner,
+		repo:   repo,
+	}
+}
+
+func (l *tagLister) ListTags(ctx context.Context) ([]string, error) {
+	tags := []string{}
+	req := &octo.ReposListTagsReq{
+		Owner:   l.owner,
+		Repo:    l.repo,
+		PerPage: octo.Int64(100),
+	}
+	for {
+		resp, err := l.client.ReposListTags(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range *resp.Data {
+			tags = append(tags, tag.Name)
+		}
+		if !req.Rel(octo.RelNext, resp) {
+			return tags, nil
+		}
+	}
+}
+
+type releaseTagLister struct {
+	client octo.Client
+	owner  string
+	repo   string
+}
+
+// NewReleaseTagLister returns a TagLister that lists the tags of a repository's published GitHub releases. Draft
+// releases are skipped.
+func NewReleaseTagLister(owner, repo string, opt ...octo.RequestOption) conventionalpulls.TagLister {
+	return &releaseTagLister{
+		client: opt,
+		owner:  owner,
+		repo:   repo,
+	}
+}
+
+func (l *releaseTagLister) ListTags(ctx context.Context) ([]string, error) {
+	tags := []string{}
+	req := &octo.ReposListReleasesReq{
+		Owner:   l.owner,
+		Repo:    l.repo,
+		PerPage: octo.Int64(100),
+	}
+	for {
+		resp, err := l.client.ReposListReleases(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, release := range *resp.Data {
+			if release.Draft {
+				continue
+			}
+			tags = append(tags, release.TagName)
+		}
+		if !req.Rel(octo.RelNext, resp) {
+			return tags, nil
+		}
+	}
+}
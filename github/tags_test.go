@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/octo-go"
+	"github.com/willabides/octo-go/components"
+	"github.com/willabides/octo-go/octotest"
+)
+
+func TestNewTagLister(t *testing.T) {
+	t.Run("paging", func(t *testing.T) {
+		ctx := context.Background()
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		page2 := &octo.ReposListTagsReq{
+			Owner:   "foo",
+			Repo:    "bar",
+			PerPage: octo.Int64(100),
+			Page:    octo.Int64(2),
+		}
+		server.Expect(page2, octotest.JSONResponder(200, &octo.ReposListTagsResponseBody{
+			{Name: "v0.1.0"},
+		}))
+		server.Expect(&octo.ReposListTagsReq{
+			Owner:   "foo",
+			Repo:    "bar",
+			PerPage: octo.Int64(100),
+		}, octotest.RelLinkHandler(octo.RelNext, octotest.JSONResponder(200, &octo.ReposListTagsResponseBody{
+			{Name: "v1.0.0"},
+			{Name: "v0.2.0"},
+		}), page2, server))
+		got, err := NewTagLister("foo", "bar", server.Client()...).ListTags(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"v1.0.0", "v0.2.0", "v0.1.0"}, got)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx := context.Background()
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		server.Expect(&octo.ReposListTagsReq{
+			Owner:   "foo",
+			Repo:    "bar",
+			PerPage: octo.Int64(100),
+		}, octotest.JSONResponder(http.StatusNotFound, "not found"))
+		got, err := NewTagLister("foo", "bar", server.Client()...).ListTags(ctx)
+		require.Error(t, err)
+		require.Empty(t, got)
+	})
+}
+
+func TestNewReleaseTagLister(t *testing.T) {
+	t.Run("skips drafts", func(t *testing.T) {
+		ctx := context.Background()
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		server.Expect(&octo.ReposListReleasesReq{
+			Owner:   "foo",
+			Repo:    "bar",
+			PerPage: octo.Int64(100),
+		}, octotest.JSONResponder(200, &octo.ReposListReleasesResponseBody{
+			{TagName: "v1.1.0", Draft: true},
+			{TagName: "v1.1.0-rc.1", Prerelease: true},
+			{TagName: "v1.0.0"},
+		}))
+		got, err := NewReleaseTagLister("foo", "bar", server.Client()...).ListTags(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"v1.1.0-rc.1", "v1.0.0"}, got)
+	})
+
+	t.Run("no releases", func(t *testing.T) {
+		ctx := context.Background()
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		server.Expect(&octo.ReposListReleasesReq{
+			Owner:   "foo",
+			Repo:    "bar",
+			PerPage: octo.Int64(100),
+		}, octotest.JSONResponder(200, []components.Release2{}))
+		got, err := NewReleaseTagLister("foo", "bar", server.Client()...).ListTags(ctx)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+}
@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/octo-go"
+)
+
+// maxWebhookPayload is the largest payload GitHub sends
+const maxWebhookPayload = 25 << 20
+
+// webhookActions are the pull_request actions that can change the result of a label check
+var webhookActions = map[string]bool{
+	"opened":      true,
+	"reopened":    true,
+	"labeled":     true,
+	"unlabeled":   true,
+	"synchronize": true,
+}
+
+// WebhookHandler is an http.Handler for GitHub pull_request webhook events. It checks the labels of an open pull
+// request with LabelChecker when the pull request is opened, reopened, labeled, unlabeled or synchronized and posts
+// the result as a check run. Other events and actions are acknowledged and ignored.
+//
+// Requests are rejected with 401 Unauthorized unless their X-Hub-Signature-256 header is valid for Secret.
+type WebhookHandler struct {
+	// Secret is the webhook's secret. It is required.
+	Secret []byte
+
+	// Config returns the Config for a repository. When it is nil, every repository uses the default label values.
+	Config func(ctx context.Context, owner, repo string) (*conventionalpulls.Config, error)
+
+	// ClientOptions returns the options for requests to a repository. installationID is the GitHub App installation
+	// that sent the event, or 0 when the webhook doesn't belong to an app. Use it with octo.WithAppInstallationAuth.
+	ClientOptions func(ctx context.Context, owner, repo string, installationID int64) ([]octo.RequestOption, error)
+
+	// CheckName and DetailsURL set LabelChecker's Name and DetailsURL
+	CheckName  string
+	DetailsURL string
+}
+
+type pullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		State string `json:"state"`
+	} `json:"pull_request"`
+	Repository struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+	Installation struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
+}
+
+// ServeHTTP meets http.Handler
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayload))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !ValidWebhookSignature(h.Secret, r.Header.Get("X-Hub-Signature-256"), payload) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("X-GitHub-Event") != "pull_request" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var event pullRequestEvent
+	err = json.Unmarshal(payload, &event)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid pull_request payload: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !webhookActions[event.Action] || event.PullRequest.State != "open" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	check, err := h.check(r.Context(), &event)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, check.Title)
+}
+
+// check checks the event's pull request and posts the result
+func (h *WebhookHandler) check(ctx context.Context, event *pullRequestEvent) (*LabelCheck, error) {
+	owner, repo := event.Repository.Owner.Login, event.Repository.Name
+	cfg := &conventionalpulls.Config{}
+	if h.Config != nil {
+		var err error
+		cfg, err = h.Config(ctx, owner, repo)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var opts []octo.RequestOption
+	if h.ClientOptions != nil {
+		var err error
+		opts, err = h.ClientOptions(ctx, owner, repo, event.Installation.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	checker := NewLabelChecker(cfg, owner, repo, opts...)
+	checker.Name = h.CheckName
+	checker.DetailsURL = h.DetailsURL
+	check, err := checker.Check(ctx, event.Number)
+	if err != nil {
+		return nil, err
+	}
+	err = checker.PostCheckRun(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// ValidWebhookSignature returns true when signature is a valid X-Hub-Signature-256 header for payload. An empty
+// secret is never valid.
+func ValidWebhookSignature(secret []byte, signature string, payload []byte) bool {
+	if len(secret) == 0 || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload) //nolint:errcheck // hash writes never fail
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,167 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/octo-go"
+	"github.com/willabides/octo-go/components"
+	"github.com/willabides/octo-go/octotest"
+)
+
+var testWebhookSecret = []byte("shh")
+
+func sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload) //nolint:errcheck // hash writes never fail
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver sends a recorded webhook payload from testdata/webhooks to h
+func deliver(t *testing.T, h http.Handler, event, filename string, modify func(r *http.Request)) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, err := ioutil.ReadFile(filepath.Join("testdata", "webhooks", filename))
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(string(payload)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	req.Header.Set("X-Hub-Signature-256", sign(testWebhookSecret, payload))
+	if modify != nil {
+		modify(req)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func expectCheckRun(server *octotest.Server, conclusion, title string) {
+	server.Expect(&octo.ChecksCreateReq{
+		Owner: "foo",
+		Repo:  "bar",
+		RequestBody: octo.ChecksCreateReqBody{
+			HeadSha:    octo.String(testSHA),
+			Name:       octo.String(DefaultCheckName),
+			Status:     octo.String("completed"),
+			Conclusion: octo.String(conclusion),
+			Output: &octo.ChecksCreateReqBodyOutput{
+				Title:   octo.String(title),
+				Summary: octo.String(webhookSummary(title)),
+			},
+		},
+		AntiopePreview: true,
+	}, octotest.JSONResponder(201, &components.CheckRun{Id: 1}))
+}
+
+func webhookSummary(title string) string {
+	accepted := "\n### Accepted labels\n\n| Label | Change |\n| --- | --- |\n" +
+		"| `breaking change` | Major |\n| `minor change` | Minor |\n| `patch` | Patch |\n| `non-production change` | None |\n"
+	if title == "Missing a version label" {
+		return "#12 needs one of the accepted labels before it is merged.\n" + accepted
+	}
+	return "#12 is labeled for a minor version change.\n\n" +
+		"| Label | Matched rule | Change |\n| --- | --- | --- |\n" +
+		"| minor change | label `minor change` | Minor |\n" + accepted
+}
+
+func testWebhookHandler(server *octotest.Server, installationID *int64) *WebhookHandler {
+	return &WebhookHandler{
+		Secret: testWebhookSecret,
+		ClientOptions: func(_ context.Context, owner, repo string, id int64) ([]octo.RequestOption, error) {
+			if owner != "foo" || repo != "bar" {
+				return nil, assert.AnError
+			}
+			*installationID = id
+			return server.Client(), nil
+		},
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	t.Run("opened", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectPull(server)
+		expectCheckRun(server, "failure", "Missing a version label")
+		var installationID int64
+		rec := deliver(t, testWebhookHandler(server, &installationID), "pull_request", "pull_request_opened.json", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		require.Equal(t, "Missing a version label\n", rec.Body.String())
+		require.Equal(t, int64(1234), installationID)
+	})
+
+	t.Run("labeled", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		expectPull(server, "Minor Change")
+		expectCheckRun(server, "success", "Labeled for a minor version change")
+		var installationID int64
+		rec := deliver(t, testWebhookHandler(server, &installationID), "pull_request", "pull_request_labeled.json", nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		require.Equal(t, "Labeled for a minor version change\n", rec.Body.String())
+	})
+
+	t.Run("Config", func(t *testing.T) {
+		server := octotest.New()
+		t.Cleanup(server.Finish)
+		var installationID int64
+		h := testWebhookHandler(server, &installationID)
+		h.Config = func(context.Context, string, string) (*conventionalpulls.Config, error) {
+			return nil, assert.AnError
+		}
+		rec := deliver(t, h, "pull_request", "pull_request_labeled.json", nil)
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Equal(t, assert.AnError.Error()+"\n", rec.Body.String())
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		h := &WebhookHandler{Secret: testWebhookSecret}
+		rec := deliver(t, h, "pull_request", "pull_request_closed.json", nil)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		rec = deliver(t, h, "ping", "ping.json", nil)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		h := &WebhookHandler{Secret: testWebhookSecret}
+		rec := deliver(t, h, "pull_request", "pull_request_opened.json", func(r *http.Request) {
+			r.Header.Set("X-Hub-Signature-256", sign([]byte("wrong"), []byte("{}")))
+		})
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		rec = deliver(t, h, "pull_request", "pull_request_opened.json", func(r *http.Request) {
+			r.Header.Del("X-Hub-Signature-256")
+		})
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		h := &WebhookHandler{Secret: testWebhookSecret}
+		rec := deliver(t, h, "pull_request", "pull_request_opened.json", func(r *http.Request) {
+			r.Method = http.MethodGet
+		})
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		require.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+	})
+}
+
+func TestValidWebhookSignature(t *testing.T) {
+	payload := []byte(`{"zen":"Keep it logically awesome."}`)
+	signature := sign(testWebhookSecret, payload)
+	require.True(t, ValidWebhookSignature(testWebhookSecret, signature, payload))
+	require.False(t, ValidWebhookSignature(testWebhookSecret, signature, []byte(`{}`)))
+	require.False(t, ValidWebhookSignature([]byte("wrong"), signature, payload))
+	require.False(t, ValidWebhookSignature(nil, sign(nil, payload), payload))
+	require.False(t, ValidWebhookSignature(testWebhookSecret, strings.TrimPrefix(signature, "sha256="), payload))
+	require.False(t, ValidWebhookSignature(testWebhookSecret, "sha256=zz", payload))
+}
@@ -0,0 +1,99 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+type prDiscoverer struct {
+	client *client
+}
+
+// NewPRDiscoverer returns a PRDiscoverer that uses GitLab's compare API to find the commits between two refs and
+// returns the merged merge requests whose merge or squash commits are among them. Merge requests that were
+// fast-forward merged have no merge commit, so any of their commits being in range is enough.
+func NewPRDiscoverer(project string, opt ...Option) conventionalpulls.PRDiscoverer {
+	return &prDiscoverer{
+		client: newClient(project, opt),
+	}
+}
+
+func (d *prDiscoverer) DiscoverPRs(ctx context.Context, baseRef, headRef string) ([]int, error) {
+	shas, err := d.compareCommits(ctx, baseRef, headRef)
+	if err != nil {
+		return nil, err
+	}
+	inRange := make(map[string]bool, len(shas))
+	for _, sha := range shas {
+		inRange[sha] = true
+	}
+	found := map[int]bool{}
+	for _, sha := range shas {
+		var mrs []int
+		mrs, err = d.mergedMRs(ctx, sha, inRange)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range mrs {
+			found[id] = true
+		}
+	}
+	prIDs := make([]int, 0, len(found))
+	for id := range found {
+		prIDs = append(prIDs, id)
+	}
+	sort.Ints(prIDs)
+	return prIDs, nil
+}
+
+// compareCommits returns the shas of all commits that are in headRef but not baseRef
+func (d *prDiscoverer) compareCommits(ctx context.Context, baseRef, headRef string) ([]string, error) {
+	var comparison struct {
+		Commits []struct {
+			ID string `json:"id"`
+		} `json:"commits"`
+	}
+	query := url.Values{}
+	query.Set("from", baseRef)
+	query.Set("to", headRef)
+	_, err := d.client.get(ctx, "/repository/compare", query, &comparison)
+	if err != nil {
+		return nil, err
+	}
+	shas := make([]string, len(comparison.Commits))
+	for i, commit := range comparison.Commits {
+		shas[i] = commit.ID
+	}
+	return shas, nil
+}
+
+// mergedMRs returns the merge requests associated with sha that were merged by a commit in inRange
+func (d *prDiscoverer) mergedMRs(ctx context.Context, sha string, inRange map[string]bool) ([]int, error) {
+	var ids []int
+	err := d.client.getPages(ctx, fmt.Sprintf("/repository/commits/%s/merge_requests", url.PathEscape(sha)), nil, func(data json.RawMessage) error {
+		var page []mergeRequest
+		err := json.Unmarshal(data, &page)
+		if err != nil {
+			return err
+		}
+		for _, mr := range page {
+			if mr.State != "merged" {
+				continue
+			}
+			fastForward := mr.MergeCommitSHA == "" && mr.SquashCommitSHA == ""
+			if fastForward || inRange[mr.MergeCommitSHA] || inRange[mr.SquashCommitSHA] {
+				ids = append(ids, mr.IID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,73 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls/internal/rest/resttest"
+)
+
+func TestNewPRDiscoverer(t *testing.T) {
+	commitMRs := func(mrs ...map[string]interface{}) http.HandlerFunc {
+		return resttest.JSONHandler(mrs)
+	}
+	mr := func(iid int, state, mergeSHA, squashSHA string) map[string]interface{} {
+		m := map[string]interface{}{"iid": iid, "state": state, "merge_commit_sha": nil, "squash_commit_sha": nil}
+		if mergeSHA != "" {
+			m["merge_commit_sha"] = mergeSHA
+		}
+		if squashSHA != "" {
+			m["squash_commit_sha"] = squashSHA
+		}
+		return m
+	}
+	const commitsPath = "/api/v4/projects/foo%2Fbar/repository/commits/"
+
+	server := testServer(t, map[string]http.HandlerFunc{
+		"/api/v4/projects/foo%2Fbar/repository/compare": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("from") != "v1.0.0" || r.URL.Query().Get("to") != "main" {
+				http.Error(w, "unexpected refs", http.StatusBadRequest)
+				return
+			}
+			resttest.JSONHandler(map[string]interface{}{
+				"commits": []map[string]string{{"id": "a1"}, {"id": "b2"}, {"id": "c3"}, {"id": "d4"}, {"id": "e5"}, {"id": "f6"}},
+			})(w, r)
+		},
+		// a1 is a commit from MR 3, which was merged by merge commit b2
+		commitsPath + "a1/merge_requests": commitMRs(mr(3, "merged", "b2", "")),
+		commitsPath + "b2/merge_requests": commitMRs(mr(3, "merged", "b2", "")),
+		// c3 is the squash commit for MR 1. MR 2 is still open.
+		commitsPath + "c3/merge_requests": commitMRs(mr(1, "merged", "", "c3"), mr(2, "opened", "", "")),
+		// d4 was fast-forward merged from MR 5 and is also in MR 4, which was merged before the base
+		commitsPath + "d4/merge_requests": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "2" {
+				commitMRs(mr(4, "merged", "zz", ""))(w, r)
+				return
+			}
+			w.Header().Set("X-Next-Page", "2")
+			commitMRs(mr(5, "merged", "", ""))(w, r)
+		},
+		commitsPath + "e5/merge_requests": commitMRs(),
+		// f6 was fast-forward merged from MR 7, which is on the page after MR 6's merge commit
+		commitsPath + "f6/merge_requests": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "2" {
+				commitMRs(mr(7, "merged", "", ""))(w, r)
+				return
+			}
+			w.Header().Set("X-Next-Page", "2")
+			commitMRs(mr(6, "merged", "zz", "yy"))(w, r)
+		},
+	})
+
+	discoverer := NewPRDiscoverer("foo/bar", testOptions(server)...)
+	got, err := discoverer.DiscoverPRs(context.Background(), "v1.0.0", "main")
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 3, 5, 7}, got)
+
+	t.Run("error", func(t *testing.T) {
+		_, err := discoverer.DiscoverPRs(context.Background(), "v0.1.0", "main")
+		require.EqualError(t, err, "GitLab returned 400 Bad Request")
+	})
+}
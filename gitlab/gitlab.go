@@ -0,0 +1,186 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/conventionalpulls/internal/rest"
+)
+
+// DefaultBaseURL is the base URL for gitlab.com's REST API
+const DefaultBaseURL = "https://gitlab.com/api/v4"
+
+// Option configures requests to GitLab
+type Option func(c *client)
+
+// WithBaseURL sets the base URL for GitLab's REST API. Self-hosted instances are usually at
+// "https://<host>/api/v4". The default is DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *client) {
+		c.SetBaseURL(baseURL)
+	}
+}
+
+// WithToken authenticates requests with a personal, project or group access token
+func WithToken(token string) Option {
+	return func(c *client) {
+		c.Auth = func(req *http.Request) {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+	}
+}
+
+// WithHTTPClient sets the http.Client used for requests. The default is http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+type client struct {
+	rest.Client
+	project string
+}
+
+// newClient returns a client for project, which is either a numeric project ID or a path like "group/project"
+func newClient(project string, opts []Option) *client {
+	c := &client{
+		Client: rest.Client{
+			BaseURL: DefaultBaseURL,
+			Service: "GitLab",
+		},
+		project: project,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// get sends a GET request for the project path p and decodes the JSON response into v. It returns the next page
+// number from the X-Next-Page header, or 0 on the last page.
+func (c *client) get(ctx context.Context, p string, query url.Values, v interface{}) (int, error) {
+	header, err := c.Get(ctx, c.URL("/projects/"+url.PathEscape(c.project)+p, query), v)
+	if err != nil {
+		return 0, err
+	}
+	nextPage := header.Get("X-Next-Page")
+	if nextPage == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(nextPage)
+}
+
+// getPages calls get for each page of a paginated list. fn is called with each page's undecoded JSON so every page
+// is decoded into a fresh value.
+func (c *client) getPages(ctx context.Context, p string, query url.Values, fn func(data json.RawMessage) error) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", "100")
+	for page := 1; page != 0; {
+		query.Set("page", strconv.Itoa(page))
+		var data json.RawMessage
+		var err error
+		page, err = c.get(ctx, p, query, &data)
+		if err != nil {
+			return err
+		}
+		err = fn(data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type mergeRequest struct {
+	IID             int      `json:"iid"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	State           string   `json:"state"`
+	WebURL          string   `json:"web_url"`
+	Labels          []string `json:"labels"`
+	MergeCommitSHA  string   `json:"merge_commit_sha"`
+	SquashCommitSHA string   `json:"squash_commit_sha"`
+	Author          struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+func (c *client) getMergeRequest(ctx context.Context, iid int) (*mergeRequest, error) {
+	var mr mergeRequest
+	_, err := c.get(ctx, fmt.Sprintf("/merge_requests/%d", iid), nil, &mr)
+	if err != nil {
+		return nil, err
+	}
+	return &mr, nil
+}
+
+type prLabelFetcher struct {
+	client *client
+	getCtx func() context.Context
+}
+
+// NewPRLabelFetcher returns a PRLabelFetcher that queries GitLab for merge request labels. project is either a
+// numeric project ID or a path like "group/project". PR IDs are merge request IIDs (the number in the merge
+// request's URL). Merge requests are fetched with ctx unless the fetcher is called as a
+// conventionalpulls.ContextPRLabelFetcher.
+func NewPRLabelFetcher(ctx context.Context, project string, opt ...Option) conventionalpulls.PRLabelFetcher {
+	return &prLabelFetcher{
+		client: newClient(project, opt),
+		getCtx: func() context.Context {
+			return ctx
+		},
+	}
+}
+
+func (f *prLabelFetcher) FetchPRLabels(id int) ([]string, error) {
+	return f.FetchPRLabelsContext(f.getCtx(), id)
+}
+
+func (f *prLabelFetcher) FetchPRLabelsContext(ctx context.Context, id int) ([]string, error) {
+	mr, err := f.client.getMergeRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]string, len(mr.Labels))
+	copy(labels, mr.Labels)
+	return labels, nil
+}
+
+type prFetcher struct {
+	client *client
+}
+
+// NewPRFetcher returns a PRFetcher that queries GitLab for merge requests
+func NewPRFetcher(project string, opt ...Option) conventionalpulls.PRFetcher {
+	return &prFetcher{
+		client: newClient(project, opt),
+	}
+}
+
+func (f *prFetcher) FetchPR(ctx context.Context, id int) (*conventionalpulls.PullRequest, error) {
+	mr, err := f.client.getMergeRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]string, len(mr.Labels))
+	copy(labels, mr.Labels)
+	return &conventionalpulls.PullRequest{
+		Number: mr.IID,
+		Title:  mr.Title,
+		Body:   mr.Description,
+		Author: mr.Author.Username,
+		URL:    mr.WebURL,
+		Labels: labels,
+	}, nil
+}
+
+// ResponseErr is an error indicating GitLab responded with a non-2xx status
+type ResponseErr = rest.ResponseErr
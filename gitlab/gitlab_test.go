@@ -0,0 +1,119 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls"
+	"github.com/willabides/conventionalpulls/internal/rest/resttest"
+)
+
+// testServer returns a GitLab stand-in that serves routes by escaped path
+func testServer(t *testing.T, routes map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	return resttest.NewServer(t, "PRIVATE-TOKEN", resttest.Token, routes)
+}
+
+func testOptions(server *httptest.Server) []Option {
+	return []Option{
+		WithBaseURL(server.URL + "/api/v4/"),
+		WithToken(resttest.Token),
+		WithHTTPClient(server.Client()),
+	}
+}
+
+var testMR = map[string]interface{}{
+	"iid":         12,
+	"title":       "feat: add widgets",
+	"description": "adds widgets",
+	"state":       "merged",
+	"web_url":     "https://gitlab.example.com/foo/bar/-/merge_requests/12",
+	"labels":      []string{"label 1", "label 2"},
+	"author":      map[string]interface{}{"username": "octocat"},
+}
+
+func TestNewPRLabelFetcher(t *testing.T) {
+	server := testServer(t, map[string]http.HandlerFunc{
+		"/api/v4/projects/foo%2Fbar/merge_requests/12": resttest.JSONHandler(testMR),
+		"/api/v4/projects/foo%2Fbar/merge_requests/13": resttest.JSONHandler(map[string]interface{}{"iid": 13, "labels": []string{}}),
+	})
+
+	t.Run("has labels", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(context.Background(), "foo/bar", testOptions(server)...)
+		got, err := fetcher.FetchPRLabels(12)
+		require.NoError(t, err)
+		require.Equal(t, []string{"label 1", "label 2"}, got)
+	})
+
+	t.Run("no labels", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(context.Background(), "foo/bar", testOptions(server)...)
+		got, err := fetcher.FetchPRLabels(13)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(context.Background(), "foo/bar", testOptions(server)...)
+		got, err := fetcher.FetchPRLabels(14)
+		require.EqualError(t, err, "GitLab returned 404 Not Found")
+		require.Equal(t, &ResponseErr{
+			Service:    "GitLab",
+			StatusCode: 404,
+			Body:       `{"message":"not found"}`,
+		}, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		fetcher := NewPRLabelFetcher(context.Background(), "foo/bar", WithBaseURL(server.URL+"/api/v4"))
+		_, err := fetcher.FetchPRLabels(12)
+		require.IsType(t, &ResponseErr{}, err)
+		require.Equal(t, http.StatusUnauthorized, err.(*ResponseErr).StatusCode)
+	})
+
+	t.Run("FetchPRLabelsContext", func(t *testing.T) {
+		canceledCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fetcher := NewPRLabelFetcher(canceledCtx, "foo/bar", testOptions(server)...)
+		ctxFetcher, ok := fetcher.(conventionalpulls.ContextPRLabelFetcher)
+		require.True(t, ok)
+		got, err := ctxFetcher.FetchPRLabelsContext(context.Background(), 12)
+		require.NoError(t, err)
+		require.Equal(t, []string{"label 1", "label 2"}, got)
+		_, err = ctxFetcher.FetchPRLabelsContext(canceledCtx, 12)
+		require.Error(t, err)
+	})
+
+	t.Run("numeric project ID", func(t *testing.T) {
+		server := testServer(t, map[string]http.HandlerFunc{
+			"/api/v4/projects/42/merge_requests/12": resttest.JSONHandler(testMR),
+		})
+		fetcher := NewPRLabelFetcher(context.Background(), "42", testOptions(server)...)
+		got, err := fetcher.FetchPRLabels(12)
+		require.NoError(t, err)
+		require.Equal(t, []string{"label 1", "label 2"}, got)
+	})
+}
+
+func TestNewPRFetcher(t *testing.T) {
+	server := testServer(t, map[string]http.HandlerFunc{
+		"/api/v4/projects/foo%2Fbar/merge_requests/12": resttest.JSONHandler(testMR),
+	})
+	fetcher := NewPRFetcher("foo/bar", testOptions(server)...)
+	got, err := fetcher.FetchPR(context.Background(), 12)
+	require.NoError(t, err)
+	require.Equal(t, &conventionalpulls.PullRequest{
+		Number: 12,
+		Title:  "feat: add widgets",
+		Body:   "adds widgets",
+		Author: "octocat",
+		URL:    "https://gitlab.example.com/foo/bar/-/merge_requests/12",
+		Labels: []string{"label 1", "label 2"},
+	}, got)
+
+	_, err = fetcher.FetchPR(context.Background(), 13)
+	require.IsType(t, &ResponseErr{}, err)
+}
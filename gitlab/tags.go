@@ -0,0 +1,40 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/willabides/conventionalpulls"
+)
+
+type tagLister struct {
+	client *client
+}
+
+// NewTagLister returns a TagLister that lists a project's tags from GitLab
+func NewTagLister(project string, opt ...Option) conventionalpulls.TagLister {
+	return &tagLister{
+		client: newClient(project, opt),
+	}
+}
+
+func (l *tagLister) ListTags(ctx context.Context) ([]string, error) {
+	tags := []string{}
+	err := l.client.getPages(ctx, "/repository/tags", nil, func(data json.RawMessage) error {
+		var page []struct {
+			Name string `json:"name"`
+		}
+		err := json.Unmarshal(data, &page)
+		if err != nil {
+			return err
+		}
+		for _, tag := range page {
+			tags = append(tags, tag.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
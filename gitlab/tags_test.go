@@ -0,0 +1,33 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls/internal/rest/resttest"
+)
+
+func TestNewTagLister(t *testing.T) {
+	server := testServer(t, map[string]http.HandlerFunc{
+		"/api/v4/projects/foo%2Fbar/repository/tags": func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "100", r.URL.Query().Get("per_page"))
+			page := r.URL.Query().Get("page")
+			if page == "1" {
+				w.Header().Set("X-Next-Page", "2")
+			}
+			resttest.JSONHandler([]map[string]string{
+				{"name": fmt.Sprintf("v%s.0.0", page)},
+				{"name": fmt.Sprintf("v%s.1.0", page)},
+			})(w, r)
+		},
+	})
+	got, err := NewTagLister("foo/bar", testOptions(server)...).ListTags(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"v1.0.0", "v1.1.0", "v2.0.0", "v2.1.0"}, got)
+
+	_, err = NewTagLister("foo/baz", testOptions(server)...).ListTags(context.Background())
+	require.IsType(t, &ResponseErr{}, err)
+}
@@ -7,4 +7,5 @@ require (
 	github.com/golang/mock v1.4.3
 	github.com/stretchr/testify v1.5.1
 	github.com/willabides/octo-go v0.3.0
+	gopkg.in/yaml.v3 v3.0.1
 )
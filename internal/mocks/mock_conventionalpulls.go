@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -46,3 +47,117 @@ func (mr *MockPRLabelFetcherMockRecorder) FetchPRLabels(id interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPRLabels", reflect.TypeOf((*MockPRLabelFetcher)(nil).FetchPRLabels), id)
 }
+
+// MockContextPRLabelFetcher is a mock of ContextPRLabelFetcher interface
+type MockContextPRLabelFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextPRLabelFetcherMockRecorder
+}
+
+// MockContextPRLabelFetcherMockRecorder is the mock recorder for MockContextPRLabelFetcher
+type MockContextPRLabelFetcherMockRecorder struct {
+	mock *MockContextPRLabelFetcher
+}
+
+// NewMockContextPRLabelFetcher creates a new mock instance
+func NewMockContextPRLabelFetcher(ctrl *gomock.Controller) *MockContextPRLabelFetcher {
+	mock := &MockContextPRLabelFetcher{ctrl: ctrl}
+	mock.recorder = &MockContextPRLabelFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockContextPRLabelFetcher) EXPECT() *MockContextPRLabelFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchPRLabelsContext mocks base method
+func (m *MockContextPRLabelFetcher) FetchPRLabelsContext(ctx context.Context, id int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPRLabelsContext", ctx, id)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPRLabelsContext indicates an expected call of FetchPRLabelsContext
+func (mr *MockContextPRLabelFetcherMockRecorder) FetchPRLabelsContext(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPRLabelsContext", reflect.TypeOf((*MockContextPRLabelFetcher)(nil).FetchPRLabelsContext), ctx, id)
+}
+
+// MockBatchPRLabelFetcher is a mock of BatchPRLabelFetcher interface
+type MockBatchPRLabelFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchPRLabelFetcherMockRecorder
+}
+
+// MockBatchPRLabelFetcherMockRecorder is the mock recorder for MockBatchPRLabelFetcher
+type MockBatchPRLabelFetcherMockRecorder struct {
+	mock *MockBatchPRLabelFetcher
+}
+
+// NewMockBatchPRLabelFetcher creates a new mock instance
+func NewMockBatchPRLabelFetcher(ctrl *gomock.Controller) *MockBatchPRLabelFetcher {
+	mock := &MockBatchPRLabelFetcher{ctrl: ctrl}
+	mock.recorder = &MockBatchPRLabelFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBatchPRLabelFetcher) EXPECT() *MockBatchPRLabelFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchPRLabelsBatch mocks base method
+func (m *MockBatchPRLabelFetcher) FetchPRLabelsBatch(ctx context.Context, ids []int) (map[int][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPRLabelsBatch", ctx, ids)
+	ret0, _ := ret[0].(map[int][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPRLabelsBatch indicates an expected call of FetchPRLabelsBatch
+func (mr *MockBatchPRLabelFetcherMockRecorder) FetchPRLabelsBatch(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPRLabelsBatch", reflect.TypeOf((*MockBatchPRLabelFetcher)(nil).FetchPRLabelsBatch), ctx, ids)
+}
+
+// MockPRDiscoverer is a mock of PRDiscoverer interface
+type MockPRDiscoverer struct {
+	ctrl     *gomock.Controller
+	recorder *MockPRDiscovererMockRecorder
+}
+
+// MockPRDiscovererMockRecorder is the mock recorder for MockPRDiscoverer
+type MockPRDiscovererMockRecorder struct {
+	mock *MockPRDiscoverer
+}
+
+// NewMockPRDiscoverer creates a new mock instance
+func NewMockPRDiscoverer(ctrl *gomock.Controller) *MockPRDiscoverer {
+	mock := &MockPRDiscoverer{ctrl: ctrl}
+	mock.recorder = &MockPRDiscovererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPRDiscoverer) EXPECT() *MockPRDiscovererMockRecorder {
+	return m.recorder
+}
+
+// DiscoverPRs mocks base method
+func (m *MockPRDiscoverer) DiscoverPRs(ctx context.Context, baseRef, headRef string) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscoverPRs", ctx, baseRef, headRef)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiscoverPRs indicates an expected call of DiscoverPRs
+func (mr *MockPRDiscovererMockRecorder) DiscoverPRs(ctx, baseRef, headRef interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscoverPRs", reflect.TypeOf((*MockPRDiscoverer)(nil).DiscoverPRs), ctx, baseRef, headRef)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tags.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockTagLister is a mock of TagLister interface
+type MockTagLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagListerMockRecorder
+}
+
+// MockTagListerMockRecorder is the mock recorder for MockTagLister
+type MockTagListerMockRecorder struct {
+	mock *MockTagLister
+}
+
+// NewMockTagLister creates a new mock instance
+func NewMockTagLister(ctrl *gomock.Controller) *MockTagLister {
+	mock := &MockTagLister{ctrl: ctrl}
+	mock.recorder = &MockTagListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTagLister) EXPECT() *MockTagListerMockRecorder {
+	return m.recorder
+}
+
+// ListTags mocks base method
+func (m *MockTagLister) ListTags(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags
+func (mr *MockTagListerMockRecorder) ListTags(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockTagLister)(nil).ListTags), ctx)
+}
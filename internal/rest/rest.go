@@ -0,0 +1,92 @@
+// Package rest is a client for the JSON REST APIs of GitLab, Gitea and Bitbucket. Each host's package keeps its own
+// paths, pagination and response mapping.
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client sends GET requests to a JSON REST API
+type Client struct {
+	// BaseURL is prepended to the paths passed to URL. It has no trailing slash.
+	BaseURL string
+
+	// Service names the API in ResponseErr messages
+	Service string
+
+	// Auth adds credentials to a request. Requests are unauthenticated when it is nil.
+	Auth func(req *http.Request)
+
+	// HTTPClient sends requests. The default is http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// SetBaseURL sets BaseURL without a trailing slash
+func (c *Client) SetBaseURL(baseURL string) {
+	c.BaseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// URL returns the URL for the path p
+func (c *Client) URL(p string, query url.Values) string {
+	u := c.BaseURL + p
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// Get sends a GET request to u and decodes the JSON response into v. It returns the response's header for
+// pagination.
+func (c *Client) Get(ctx context.Context, u string, v interface{}) (http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if c.Auth != nil {
+		c.Auth(req)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck // nothing to do with this error
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &ResponseErr{
+			Service:    c.Service,
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(body)),
+		}
+	}
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Header, nil
+}
+
+// ResponseErr is an error indicating the API responded with a non-2xx status
+type ResponseErr struct {
+	Service    string
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseErr) Error() string {
+	return fmt.Sprintf("%s returned %d %s", e.Service, e.StatusCode, http.StatusText(e.StatusCode))
+}
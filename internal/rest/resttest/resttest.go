@@ -0,0 +1,41 @@
+// Package resttest provides stand-in REST API servers for testing the GitLab, Gitea and Bitbucket packages
+package resttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Token is the access token test servers expect
+const Token = "secret"
+
+// NewServer returns a server that serves routes by escaped path. Requests are rejected with 401 Unauthorized unless
+// their authHeader header is authValue, and unknown paths get 404 Not Found. The server is closed when the test
+// finishes.
+func NewServer(t *testing.T, authHeader, authValue string, routes map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(authHeader) != authValue {
+			http.Error(w, `{"message":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		handler, ok := routes[r.URL.EscapedPath()]
+		if !ok {
+			http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// JSONHandler returns a handler that responds with body encoded as JSON
+func JSONHandler(body interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body) //nolint:errcheck // test server
+	}
+}
@@ -0,0 +1,155 @@
+package conventionalpulls
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LabelRule maps every label matching a pattern to a VersionChange. Create LabelRules with GlobLabelRule or
+// RegexpLabelRule.
+type LabelRule struct {
+	pattern       string
+	ruleType      string
+	exp           *regexp.Regexp
+	versionChange VersionChange
+}
+
+// GlobLabelRule returns a LabelRule for labels matching a glob pattern. "*" matches any sequence of characters, "?"
+// matches any single character, "[...]" matches a character class ("[!...]" negates it) and "\" escapes the
+// following character. Matching is case-insensitive and the pattern must match the whole label.
+//
+// Returns an *InvalidLabelPatternErr when pattern isn't a valid glob.
+func GlobLabelRule(pattern string, versionChange VersionChange) (*LabelRule, error) {
+	expr, err := globExpression(pattern)
+	if err != nil {
+		return nil, &InvalidLabelPatternErr{Pattern: pattern, err: err}
+	}
+	return newLabelRule(pattern, "glob", expr, versionChange)
+}
+
+// RegexpLabelRule returns a LabelRule for labels matching a regular expression. Matching is case-insensitive and
+// unanchored, so use "^" and "$" to match the whole label.
+//
+// Returns an *InvalidLabelPatternErr when pattern isn't a valid regular expression.
+func RegexpLabelRule(pattern string, versionChange VersionChange) (*LabelRule, error) {
+	return newLabelRule(pattern, "regexp", pattern, versionChange)
+}
+
+func newLabelRule(pattern, ruleType, expr string, versionChange VersionChange) (*LabelRule, error) {
+	if !versionChange.valid() {
+		return nil, &InvalidLabelPatternErr{
+			Pattern: pattern,
+			err:     fmt.Errorf("%d is not a valid VersionChange", versionChange),
+		}
+	}
+	exp, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, &InvalidLabelPatternErr{Pattern: pattern, err: err}
+	}
+	return &LabelRule{
+		pattern:       pattern,
+		ruleType:      ruleType,
+		exp:           exp,
+		versionChange: versionChange,
+	}, nil
+}
+
+// Pattern returns the pattern the rule was created with
+func (r *LabelRule) Pattern() string {
+	return r.pattern
+}
+
+// Type returns "glob" or "regexp"
+func (r *LabelRule) Type() string {
+	return r.ruleType
+}
+
+// VersionChange returns the change for labels matching the rule
+func (r *LabelRule) VersionChange() VersionChange {
+	return r.versionChange
+}
+
+// Match returns true when label matches the rule
+func (r *LabelRule) Match(label string) bool {
+	return r.exp.MatchString(label)
+}
+
+// globExpression converts a glob pattern to an anchored regular expression
+func globExpression(pattern string) (string, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '\\':
+			i++
+			if i == len(runes) {
+				return "", fmt.Errorf("trailing escape character")
+			}
+			expr.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			end, err := globClass(&expr, runes, i)
+			if err != nil {
+				return "", err
+			}
+			i = end
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	return expr.String(), nil
+}
+
+// globClass writes the regular expression for the "[...]" character class starting at runes[start] to expr. It
+// returns the index of the class's closing "]".
+func globClass(expr *strings.Builder, runes []rune, start int) (int, error) {
+	end := start + 1
+	if end < len(runes) && runes[end] == '!' {
+		end++
+	}
+	// a "]" at the start of a class is a literal
+	if end < len(runes) && runes[end] == ']' {
+		end++
+	}
+	for end < len(runes) && runes[end] != ']' {
+		end++
+	}
+	if end == len(runes) {
+		return 0, fmt.Errorf("unterminated character class")
+	}
+	class := runes[start+1 : end]
+	expr.WriteString("[")
+	if len(class) > 0 && class[0] == '!' {
+		expr.WriteString("^")
+		class = class[1:]
+	}
+	for _, cc := range class {
+		if cc == '\\' || cc == '[' || cc == ']' || cc == '^' {
+			expr.WriteString(`\`)
+		}
+		expr.WriteRune(cc)
+	}
+	expr.WriteString("]")
+	return end, nil
+}
+
+// InvalidLabelPatternErr is returned when a LabelRule pattern can't be compiled
+type InvalidLabelPatternErr struct {
+	Pattern string
+	err     error
+}
+
+// Unwrap meets xerrors.Wrapper
+func (e *InvalidLabelPatternErr) Unwrap() error {
+	return e.err
+}
+
+func (e *InvalidLabelPatternErr) Error() string {
+	return fmt.Sprintf("invalid label pattern %q: %v", e.Pattern, e.err)
+}
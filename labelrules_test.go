@@ -0,0 +1,148 @@
+package conventionalpulls
+
+import (
+	"errors"
+	"regexp/syntax"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobLabelRule(t *testing.T) {
+	for _, td := range []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{
+			pattern: "semver:*",
+			matches: []string{"semver:major", "SemVer:Minor", "semver:"},
+			misses:  []string{"semver", "xsemver:major"},
+		},
+		{
+			pattern: "semver/minor",
+			matches: []string{"semver/minor", "SEMVER/MINOR"},
+			misses:  []string{"semver/minors", "semver-minor"},
+		},
+		{
+			pattern: "type:?breaking",
+			matches: []string{"type: breaking", "type:_breaking"},
+			misses:  []string{"type:breaking", "type:  breaking"},
+		},
+		{
+			pattern: "v[12].x",
+			matches: []string{"v1.x", "v2.x"},
+			misses:  []string{"v3.x", "v1x"},
+		},
+		{
+			pattern: "[!a-c]*",
+			matches: []string{"d", "xyz"},
+			misses:  []string{"a", "bcd", ""},
+		},
+		{
+			pattern: `\*\?`,
+			matches: []string{"*?"},
+			misses:  []string{"a?", "*a"},
+		},
+		{
+			pattern: "[]]",
+			matches: []string{"]"},
+			misses:  []string{"a"},
+		},
+	} {
+		rule, err := GlobLabelRule(td.pattern, VersionChangeMinor)
+		require.NoError(t, err, td.pattern)
+		require.Equal(t, td.pattern, rule.Pattern())
+		require.Equal(t, VersionChangeMinor, rule.VersionChange())
+		for _, label := range td.matches {
+			require.True(t, rule.Match(label), "%q should match %q", td.pattern, label)
+		}
+		for _, label := range td.misses {
+			require.False(t, rule.Match(label), "%q should not match %q", td.pattern, label)
+		}
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for pattern, wantErr := range map[string]string{
+			"semver:[major": `invalid label pattern "semver:[major": unterminated character class`,
+			`semver\`:       `invalid label pattern "semver\\": trailing escape character`,
+		} {
+			rule, err := GlobLabelRule(pattern, VersionChangeMinor)
+			require.EqualError(t, err, wantErr)
+			require.IsType(t, &InvalidLabelPatternErr{}, err)
+			require.Nil(t, rule)
+		}
+	})
+}
+
+func TestRegexpLabelRule(t *testing.T) {
+	rule, err := RegexpLabelRule(`^type:\s*breaking$`, VersionChangeMajor)
+	require.NoError(t, err)
+	require.True(t, rule.Match("type: breaking"))
+	require.True(t, rule.Match("Type:Breaking"))
+	require.False(t, rule.Match("type: breaking change"))
+
+	rule, err = RegexpLabelRule(`semver.major`, VersionChangeMajor)
+	require.NoError(t, err)
+	require.True(t, rule.Match("x semver/major x"))
+
+	rule, err = RegexpLabelRule(`semver(major`, VersionChangeMajor)
+	require.EqualError(t, err, "invalid label pattern \"semver(major\": error parsing regexp: missing closing ): `(?i)semver(major`")
+	var syntaxErr *syntax.Error
+	require.True(t, errors.As(err, &syntaxErr))
+	require.Nil(t, rule)
+
+	rule, err = RegexpLabelRule(`major`, versionChangeInvalid)
+	require.EqualError(t, err, `invalid label pattern "major": 4 is not a valid VersionChange`)
+	require.Nil(t, rule)
+}
+
+func mustLabelRule(rule *LabelRule, err error) *LabelRule {
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+func TestConfig_LabelRules(t *testing.T) {
+	cfg := &Config{
+		LabelValues: map[string]VersionChange{
+			"semver:none": VersionChangeNone,
+		},
+		LabelRules: []*LabelRule{
+			mustLabelRule(GlobLabelRule("semver[:/]major", VersionChangeMajor)),
+			mustLabelRule(GlobLabelRule("semver[:/]minor", VersionChangeMinor)),
+			mustLabelRule(RegexpLabelRule(`^type:\s*breaking$`, VersionChangeMajor)),
+			mustLabelRule(GlobLabelRule("semver:*", VersionChangePatch)),
+			mustLabelRule(GlobLabelRule("semver:*", VersionChangeMinor)),
+		},
+	}
+	for _, td := range []struct {
+		labels []string
+		want   VersionChange
+	}{
+		{labels: []string{"semver:major"}, want: VersionChangeMajor},
+		{labels: []string{"SEMVER/minor"}, want: VersionChangeMinor},
+		{labels: []string{"type: breaking", "semver/minor"}, want: VersionChangeMajor},
+		// an exact match takes precedence over rules
+		{labels: []string{"semver:none"}, want: VersionChangeNone},
+		// the first matching rule wins
+		{labels: []string{"semver:other"}, want: VersionChangePatch},
+		{labels: []string{"bug"}, want: VersionChangeNone},
+	} {
+		require.Equal(t, td.want, cfg.LabelsVersionChange(td.labels), "%q", td.labels)
+	}
+	require.True(t, cfg.containsAnyLabel([]string{"semver:other"}))
+	require.True(t, cfg.containsAnyLabel([]string{"semver:none"}))
+	require.False(t, cfg.containsAnyLabel([]string{"bug"}))
+
+	t.Run("with default label values", func(t *testing.T) {
+		cfg := &Config{
+			LabelRules: []*LabelRule{
+				mustLabelRule(GlobLabelRule("semver:*", VersionChangeMinor)),
+			},
+		}
+		require.Equal(t, VersionChangeMajor, cfg.LabelsVersionChange([]string{"breaking change", "semver:x"}))
+		require.Equal(t, VersionChangeMinor, cfg.LabelsVersionChange([]string{"patch", "semver:x"}))
+	})
+}
@@ -0,0 +1,18 @@
+package conventionalpulls
+
+import "context"
+
+// PullRequest is the pull request information used for release notes
+type PullRequest struct {
+	Number int
+	Title  string
+	Body   string
+	Author string
+	URL    string
+	Labels []string
+}
+
+// PRFetcher fetches pull requests from GitHub (or wherever)
+type PRFetcher interface {
+	FetchPR(ctx context.Context, id int) (*PullRequest, error)
+}
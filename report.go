@@ -0,0 +1,262 @@
+package conventionalpulls
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Report explains how a version change was decided
+type Report struct {
+	// PreviousVersion and NextVersion are empty when the report was made without a previous version
+	PreviousVersion string `json:"previous_version,omitempty"`
+	NextVersion     string `json:"next_version,omitempty"`
+
+	VersionChange VersionChange `json:"version_change"`
+
+	// DrivingPRs are the pull requests whose change is VersionChange. It is empty when VersionChange is
+	// VersionChangeNone.
+	DrivingPRs []int `json:"driving_prs"`
+
+	// PullRequests are the pull requests in the release ordered by ID
+	PullRequests []*PRReport `json:"pull_requests"`
+
+	// DrivingCommits are the SHAs of the commits whose change is VersionChange in reports made from commits
+	DrivingCommits []string `json:"driving_commits"`
+
+	// Commits are the commits in the release, oldest first, in reports made from commits
+	Commits []*CommitReport `json:"commits"`
+}
+
+// PRReport explains the change for one pull request
+type PRReport struct {
+	ID int `json:"id"`
+
+	// Labels are the pull request's lowercased labels
+	Labels []string `json:"labels"`
+
+	// Matches are the labels that have a configured change
+	Matches []LabelMatch `json:"matches"`
+
+	VersionChange VersionChange `json:"version_change"`
+}
+
+// CommitReport explains the change for one commit
+type CommitReport struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+
+	// Conventional is false when the commit message isn't a Conventional Commits message or it isn't a breaking
+	// change and its type has no configured change
+	Conventional bool `json:"conventional"`
+
+	Type     string `json:"type,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	Breaking bool   `json:"breaking"`
+
+	VersionChange VersionChange `json:"version_change"`
+}
+
+// LabelMatch is a label with a configured change
+type LabelMatch struct {
+	Label string `json:"label"`
+
+	// RuleType is "label" for an exact match in Config.LabelValues (or the default values), or the Type of the
+	// LabelRule that matched
+	RuleType string `json:"rule_type"`
+
+	// Rule is the label name or rule pattern that matched
+	Rule string `json:"rule"`
+
+	VersionChange VersionChange `json:"version_change"`
+}
+
+// Explain returns a Report for a release including the given pulls. It fetches labels and checks them like
+// PRVersionChangeContext. When prevVersion is empty, the report has no previous or next version.
+func (cfg *Config) Explain(ctx context.Context, prevVersion string, pullRequestID ...int) (*Report, error) {
+	prLabels, err := cfg.checkedPRLabels(ctx, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	prIDs := make([]int, 0, len(prLabels))
+	for id := range prLabels {
+		prIDs = append(prIDs, id)
+	}
+	sort.Ints(prIDs)
+	labelValues := cfg.labelValues()
+	report := &Report{
+		PreviousVersion: prevVersion,
+		DrivingPRs:      []int{},
+		PullRequests:    make([]*PRReport, 0, len(prIDs)),
+		DrivingCommits:  []string{},
+		Commits:         []*CommitReport{},
+	}
+	for _, id := range prIDs {
+		pr := cfg.prReport(labelValues, id, prLabels[id])
+		report.VersionChange = pr.VersionChange.greater(report.VersionChange)
+		report.PullRequests = append(report.PullRequests, pr)
+	}
+	if report.VersionChange != VersionChangeNone {
+		for _, pr := range report.PullRequests {
+			if pr.VersionChange == report.VersionChange {
+				report.DrivingPRs = append(report.DrivingPRs, pr.ID)
+			}
+		}
+	}
+	if prevVersion != "" {
+		report.NextVersion, err = cfg.nextVersion(prevVersion, report.VersionChange)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func (cfg *Config) prReport(labelValues map[string]VersionChange, id int, labels []string) *PRReport {
+	pr := &PRReport{
+		ID:      id,
+		Labels:  labels,
+		Matches: []LabelMatch{},
+	}
+	for _, label := range labels {
+		match, ok := cfg.matchLabel(labelValues, label)
+		if !ok {
+			continue
+		}
+		pr.Matches = append(pr.Matches, match)
+		pr.VersionChange = match.VersionChange.greater(pr.VersionChange)
+	}
+	return pr
+}
+
+// CheckLabels checks the labels of a single pull request before it is merged. It always requires a configured label
+// and checks StrictLabels when it is set. It returns the PRReport along with a *PRMissingLabelErr or
+// *PRConflictingLabelErr when the labels fail the check. Labels are lowercased like the labels in Explain's report.
+func (cfg *Config) CheckLabels(id int, labels []string) (*PRReport, error) {
+	lowered := make([]string, len(labels))
+	for i, label := range labels {
+		lowered[i] = strings.ToLower(label)
+	}
+	pr := cfg.prReport(cfg.labelValues(), id, lowered)
+	prLabels := map[int][]string{id: lowered}
+	required := *cfg
+	required.RequireLabels = true
+	err := required.requireLabels(prLabels)
+	if err != nil {
+		return pr, err
+	}
+	return pr, cfg.strictLabels(prLabels)
+}
+
+// AcceptedLabel is a label or label rule with a configured change
+type AcceptedLabel struct {
+	// RuleType is "label" for a label in Config.LabelValues (or the default values), or the Type of a LabelRule
+	RuleType string `json:"rule_type"`
+
+	// Rule is the lowercased label name or the rule pattern
+	Rule string `json:"rule"`
+
+	VersionChange VersionChange `json:"version_change"`
+}
+
+// AcceptedLabels returns the labels and label rules that satisfy RequireLabels. Labels are sorted from the greatest
+// change to the least and then by name. Label rules follow in the order they are checked.
+func (cfg *Config) AcceptedLabels() []AcceptedLabel {
+	labelValues := cfg.labelValues()
+	accepted := make([]AcceptedLabel, 0, len(labelValues)+len(cfg.LabelRules))
+	for label, change := range labelValues {
+		accepted = append(accepted, AcceptedLabel{
+			RuleType:      "label",
+			Rule:          label,
+			VersionChange: change,
+		})
+	}
+	sort.Slice(accepted, func(i, j int) bool {
+		if accepted[i].VersionChange != accepted[j].VersionChange {
+			return accepted[i].VersionChange > accepted[j].VersionChange
+		}
+		return accepted[i].Rule < accepted[j].Rule
+	})
+	for _, rule := range cfg.LabelRules {
+		accepted = append(accepted, AcceptedLabel{
+			RuleType:      rule.Type(),
+			Rule:          rule.Pattern(),
+			VersionChange: rule.VersionChange(),
+		})
+	}
+	return accepted
+}
+
+// Markdown writes the report as Markdown
+func (r *Report) Markdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("## Version decision\n\n")
+	if r.PreviousVersion != "" {
+		fmt.Fprintf(&b, "Previous version: %s\n", r.PreviousVersion)
+		fmt.Fprintf(&b, "Next version: %s\n", r.NextVersion)
+	}
+	fmt.Fprintf(&b, "Version change: **%s**", r.VersionChange)
+	var driving []string
+	for _, id := range r.DrivingPRs {
+		driving = append(driving, fmt.Sprintf("#%d", id))
+	}
+	for _, sha := range r.DrivingCommits {
+		driving = append(driving, shortSHA(sha))
+	}
+	if len(driving) > 0 {
+		fmt.Fprintf(&b, " (driven by %s)", strings.Join(driving, ", "))
+	}
+	b.WriteString("\n")
+	if len(r.PullRequests) > 0 {
+		b.WriteString("\n| PR | Labels | Matched rules | Change |\n| --- | --- | --- | --- |\n")
+	}
+	for _, pr := range r.PullRequests {
+		matches := make([]string, len(pr.Matches))
+		for i, match := range pr.Matches {
+			matches[i] = fmt.Sprintf("%s: %s `%s` → %s", match.Label, match.RuleType, match.Rule, match.VersionChange)
+		}
+		fmt.Fprintf(&b, "| #%d | %s | %s | %s |\n",
+			pr.ID,
+			MarkdownCell(strings.Join(pr.Labels, ", ")),
+			MarkdownCell(strings.Join(matches, "<br>")),
+			pr.VersionChange,
+		)
+	}
+	if len(r.Commits) > 0 {
+		b.WriteString("\n| Commit | Subject | Type | Change |\n| --- | --- | --- | --- |\n")
+	}
+	for _, c := range r.Commits {
+		commitType := c.Type
+		if c.Scope != "" {
+			commitType += "(" + c.Scope + ")"
+		}
+		if c.Breaking {
+			commitType += "!"
+		}
+		if !c.Conventional {
+			commitType = "not conventional"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+			shortSHA(c.SHA),
+			MarkdownCell(c.Subject),
+			MarkdownCell(commitType),
+			c.VersionChange,
+		)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// MarkdownCell escapes pipes so s can be used in a Markdown table cell
+func MarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,242 @@
+package conventionalpulls
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls/internal/mocks"
+)
+
+func testReportConfig(t *testing.T) *Config {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+	mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"Patch", "docs"}, nil).AnyTimes()
+	mockFetcher.EXPECT().FetchPRLabels(2).Return([]string{"semver:minor", "Patch"}, nil).AnyTimes()
+	mockFetcher.EXPECT().FetchPRLabels(3).Return([]string{"area|cli"}, nil).AnyTimes()
+	mockFetcher.EXPECT().FetchPRLabels(4).Return([]string{"Minor Change"}, nil).AnyTimes()
+	rule, err := GlobLabelRule("semver:minor", VersionChangeMinor)
+	require.NoError(t, err)
+	return &Config{
+		PRLabelFetcher: mockFetcher,
+		LabelRules:     []*LabelRule{rule},
+	}
+}
+
+func TestConfig_Explain(t *testing.T) {
+	t.Run("with previous version", func(t *testing.T) {
+		cfg := testReportConfig(t)
+		got, err := cfg.Explain(context.Background(), "v1.2.3", 4, 3, 2, 1)
+		require.NoError(t, err)
+		require.Equal(t, &Report{
+			PreviousVersion: "v1.2.3",
+			NextVersion:     "v1.3.0",
+			VersionChange:   VersionChangeMinor,
+			DrivingPRs:      []int{2, 4},
+			DrivingCommits:  []string{},
+			Commits:         []*CommitReport{},
+			PullRequests: []*PRReport{
+				{
+					ID:     1,
+					Labels: []string{"patch", "docs"},
+					Matches: []LabelMatch{
+						{Label: "patch", RuleType: "label", Rule: "patch", VersionChange: VersionChangePatch},
+					},
+					VersionChange: VersionChangePatch,
+				},
+				{
+					ID:     2,
+					Labels: []string{"semver:minor", "patch"},
+					Matches: []LabelMatch{
+						{Label: "semver:minor", RuleType: "glob", Rule: "semver:minor", VersionChange: VersionChangeMinor},
+						{Label: "patch", RuleType: "label", Rule: "patch", VersionChange: VersionChangePatch},
+					},
+					VersionChange: VersionChangeMinor,
+				},
+				{
+					ID:            3,
+					Labels:        []string{"area|cli"},
+					Matches:       []LabelMatch{},
+					VersionChange: VersionChangeNone,
+				},
+				{
+					ID:     4,
+					Labels: []string{"minor change"},
+					Matches: []LabelMatch{
+						{Label: "minor change", RuleType: "label", Rule: "minor change", VersionChange: VersionChangeMinor},
+					},
+					VersionChange: VersionChangeMinor,
+				},
+			},
+		}, got)
+	})
+
+	t.Run("without previous version", func(t *testing.T) {
+		cfg := testReportConfig(t)
+		got, err := cfg.Explain(context.Background(), "", 3)
+		require.NoError(t, err)
+		require.Equal(t, "", got.NextVersion)
+		require.Equal(t, VersionChangeNone, got.VersionChange)
+		require.Empty(t, got.DrivingPRs)
+	})
+
+	t.Run("missing labels", func(t *testing.T) {
+		cfg := testReportConfig(t)
+		cfg.RequireLabels = true
+		got, err := cfg.Explain(context.Background(), "v1.2.3", 1, 3)
+		require.Equal(t, &PRMissingLabelErr{IDs: []int{3}}, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("invalid previous version", func(t *testing.T) {
+		cfg := testReportConfig(t)
+		got, err := cfg.Explain(context.Background(), "foo", 1)
+		require.Error(t, err)
+		require.Nil(t, got)
+	})
+}
+
+func TestConfig_CheckLabels(t *testing.T) {
+	rule, err := GlobLabelRule("semver:*", VersionChangeMinor)
+	require.NoError(t, err)
+	cfg := &Config{LabelRules: []*LabelRule{rule}}
+
+	t.Run("labeled", func(t *testing.T) {
+		got, err := cfg.CheckLabels(12, []string{"docs", "Semver:Minor"})
+		require.NoError(t, err)
+		require.Equal(t, &PRReport{
+			ID:     12,
+			Labels: []string{"docs", "semver:minor"},
+			Matches: []LabelMatch{
+				{Label: "semver:minor", RuleType: "glob", Rule: "semver:*", VersionChange: VersionChangeMinor},
+			},
+			VersionChange: VersionChangeMinor,
+		}, got)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		got, err := cfg.CheckLabels(12, []string{"docs"})
+		require.Equal(t, &PRMissingLabelErr{IDs: []int{12}}, err)
+		require.Empty(t, got.Matches)
+	})
+
+	t.Run("conflicting", func(t *testing.T) {
+		labels := []string{"Patch", "semver:minor"}
+		_, err := cfg.CheckLabels(12, labels)
+		require.NoError(t, err)
+		strict := &Config{LabelRules: cfg.LabelRules, StrictLabels: true}
+		got, err := strict.CheckLabels(12, labels)
+		require.Equal(t, &PRConflictingLabelErr{
+			IDs:    []int{12},
+			Labels: map[int][]string{12: {"patch", "semver:minor"}},
+		}, err)
+		require.Equal(t, VersionChangeMinor, got.VersionChange)
+	})
+}
+
+func TestConfig_AcceptedLabels(t *testing.T) {
+	rule, err := RegexpLabelRule("^semver:patch$", VersionChangePatch)
+	require.NoError(t, err)
+	cfg := &Config{LabelRules: []*LabelRule{rule}}
+	require.Equal(t, []AcceptedLabel{
+		{RuleType: "label", Rule: "breaking change", VersionChange: VersionChangeMajor},
+		{RuleType: "label", Rule: "minor change", VersionChange: VersionChangeMinor},
+		{RuleType: "label", Rule: "patch", VersionChange: VersionChangePatch},
+		{RuleType: "label", Rule: "non-production change", VersionChange: VersionChangeNone},
+		{RuleType: "regexp", Rule: "^semver:patch$", VersionChange: VersionChangePatch},
+	}, cfg.AcceptedLabels())
+
+	cfg = &Config{LabelValues: map[string]VersionChange{"B": VersionChangePatch, "a": VersionChangePatch}}
+	require.Equal(t, []AcceptedLabel{
+		{RuleType: "label", Rule: "a", VersionChange: VersionChangePatch},
+		{RuleType: "label", Rule: "b", VersionChange: VersionChangePatch},
+	}, cfg.AcceptedLabels())
+}
+
+func TestReport_JSON(t *testing.T) {
+	cfg := testReportConfig(t)
+	report, err := cfg.Explain(context.Background(), "v1.2.3", 1, 3)
+	require.NoError(t, err)
+	got, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "previous_version": "v1.2.3",
+  "next_version": "v1.2.4",
+  "version_change": "Patch",
+  "driving_prs": [1],
+  "pull_requests": [
+    {
+      "id": 1,
+      "labels": ["patch", "docs"],
+      "matches": [{"label": "patch", "rule_type": "label", "rule": "patch", "version_change": "Patch"}],
+      "version_change": "Patch"
+    },
+    {
+      "id": 3,
+      "labels": ["area|cli"],
+      "matches": [],
+      "version_change": "None"
+    }
+  ],
+  "driving_commits": [],
+  "commits": []
+}`, string(got))
+	var roundTrip Report
+	err = json.Unmarshal(got, &roundTrip)
+	require.NoError(t, err)
+	require.Equal(t, report, &roundTrip)
+}
+
+func TestReport_Markdown(t *testing.T) {
+	cfg := testReportConfig(t)
+	report, err := cfg.Explain(context.Background(), "v1.2.3", 1, 2, 3, 4)
+	require.NoError(t, err)
+	var buf strings.Builder
+	err = report.Markdown(&buf)
+	require.NoError(t, err)
+	require.Equal(t, "## Version decision\n\n"+
+		"Previous version: v1.2.3\n"+
+		"Next version: v1.3.0\n"+
+		"Version change: **Minor** (driven by #2, #4)\n\n"+
+		"| PR | Labels | Matched rules | Change |\n"+
+		"| --- | --- | --- | --- |\n"+
+		"| #1 | patch, docs | patch: label `patch` → Patch | Patch |\n"+
+		"| #2 | semver:minor, patch | semver:minor: glob `semver:minor` → Minor<br>patch: label `patch` → Patch | Minor |\n"+
+		"| #3 | area\\|cli |  | None |\n"+
+		"| #4 | minor change | minor change: label `minor change` → Minor | Minor |\n",
+		buf.String())
+
+	buf.Reset()
+	err = (&Report{
+		PreviousVersion: "v1.2.3",
+		NextVersion:     "v2.0.0",
+		VersionChange:   VersionChangeMajor,
+		DrivingCommits:  []string{"2222222222"},
+		Commits: []*CommitReport{
+			{SHA: "1111111111", Subject: "feat(api): add widgets", Conventional: true, Type: "feat", Scope: "api", VersionChange: VersionChangeMinor},
+			{SHA: "2222222222", Subject: "fix!: remove | pipes", Conventional: true, Type: "fix", Breaking: true, VersionChange: VersionChangeMajor},
+			{SHA: "333", Subject: "Update README.md", VersionChange: VersionChangeNone},
+		},
+	}).Markdown(&buf)
+	require.NoError(t, err)
+	require.Equal(t, "## Version decision\n\n"+
+		"Previous version: v1.2.3\n"+
+		"Next version: v2.0.0\n"+
+		"Version change: **Major** (driven by 2222222)\n\n"+
+		"| Commit | Subject | Type | Change |\n"+
+		"| --- | --- | --- | --- |\n"+
+		"| 1111111 | feat(api): add widgets | feat(api) | Minor |\n"+
+		"| 2222222 | fix!: remove \\| pipes | fix! | Major |\n"+
+		"| 333 | Update README.md | not conventional | None |\n",
+		buf.String())
+
+	buf.Reset()
+	err = (&Report{}).Markdown(&buf)
+	require.NoError(t, err)
+	require.Equal(t, "## Version decision\n\nVersion change: **None**\n", buf.String())
+}
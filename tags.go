@@ -0,0 +1,114 @@
+package conventionalpulls
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+//go:generate mockgen -source $GOFILE -destination internal/mocks/mock_$GOFILE -package mocks
+
+// TagLister lists the tags in a repository
+type TagLister interface {
+	ListTags(ctx context.Context) (tags []string, err error)
+}
+
+// TagResolver finds the latest version tag in a repository
+type TagResolver struct {
+	TagLister TagLister
+
+	// Prefix is the part of a tag that comes before the version, such as "v" or "mymod/v". Tags without the prefix
+	// are ignored.
+	Prefix string
+
+	// IgnorePreReleases ignores tags for pre-release versions
+	IgnorePreReleases bool
+
+	// RestrictMajor limits tags to versions with the major version MajorVersion
+	RestrictMajor bool
+	MajorVersion  uint64
+}
+
+// LatestTag returns the tag with the highest version along with its version (the tag without Prefix). Returns a
+// *NoVersionTagErr when there is no matching tag.
+func (r *TagResolver) LatestTag(ctx context.Context) (tag, version string, err error) {
+	if r.TagLister == nil {
+		panic("TagLister shant be nil")
+	}
+	tags, err := r.TagLister.ListTags(ctx)
+	if err != nil {
+		return "", "", &TagListerErr{err: err}
+	}
+	var latest *semver.Version
+	for _, t := range tags {
+		v := r.tagVersion(t)
+		if v == nil {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+			tag = t
+		}
+	}
+	if latest == nil {
+		return "", "", &NoVersionTagErr{Prefix: r.Prefix}
+	}
+	return tag, latest.Original(), nil
+}
+
+// tagVersion returns the version for tag or nil if tag isn't a matching version tag
+func (r *TagResolver) tagVersion(tag string) *semver.Version {
+	if !strings.HasPrefix(tag, r.Prefix) {
+		return nil
+	}
+	v, err := semver.StrictNewVersion(strings.TrimPrefix(tag, r.Prefix))
+	if err != nil {
+		return nil
+	}
+	if r.IgnorePreReleases && v.Prerelease() != "" {
+		return nil
+	}
+	if r.RestrictMajor && v.Major() != r.MajorVersion {
+		return nil
+	}
+	return v
+}
+
+// NextTag returns the tag for the next release of headRef. The previous release is the latest tag found by resolver,
+// and the included pulls are the ones cfg.PRDiscoverer finds between it and headRef.
+func (cfg *Config) NextTag(ctx context.Context, resolver *TagResolver, headRef string) (string, error) {
+	prevTag, prevVersion, err := resolver.LatestTag(ctx)
+	if err != nil {
+		return "", err
+	}
+	next, err := cfg.NextVersionForRefs(ctx, prevVersion, prevTag, headRef)
+	if err != nil {
+		return "", err
+	}
+	return resolver.Prefix + next, nil
+}
+
+// NoVersionTagErr is an error indicating that no tag matched a TagResolver.
+type NoVersionTagErr struct {
+	Prefix string
+}
+
+func (e *NoVersionTagErr) Error() string {
+	return fmt.Sprintf("no version tags found with prefix %q", e.Prefix)
+}
+
+// TagListerErr is an error indicating a problem listing tags.
+type TagListerErr struct {
+	err error
+}
+
+// Unwrap meets xerrors.Wrapper
+func (e *TagListerErr) Unwrap() error {
+	return e.err
+}
+
+func (e *TagListerErr) Error() string {
+	return "error from TagLister"
+}
@@ -0,0 +1,155 @@
+package conventionalpulls
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/conventionalpulls/internal/mocks"
+)
+
+func TestTagResolver_LatestTag(t *testing.T) {
+	tags := []string{
+		"v1.2.3",
+		"v1.10.0",
+		"v1.9.0",
+		"v2.0.0-rc.1",
+		"v0.9.0",
+		"1.11.0",
+		"v1.11",
+		"latest",
+		"mymod/v3.1.0",
+		"mymod/v3.2.0-beta.1",
+		"mymod/v2.5.0",
+	}
+	for _, td := range []struct {
+		name        string
+		resolver    TagResolver
+		wantTag     string
+		wantVersion string
+		wantErr     error
+	}{
+		{
+			name:        "v prefix",
+			resolver:    TagResolver{Prefix: "v"},
+			wantTag:     "v2.0.0-rc.1",
+			wantVersion: "2.0.0-rc.1",
+		},
+		{
+			name:        "ignore pre-releases",
+			resolver:    TagResolver{Prefix: "v", IgnorePreReleases: true},
+			wantTag:     "v1.10.0",
+			wantVersion: "1.10.0",
+		},
+		{
+			name:        "no prefix",
+			resolver:    TagResolver{},
+			wantTag:     "1.11.0",
+			wantVersion: "1.11.0",
+		},
+		{
+			name:        "module prefix",
+			resolver:    TagResolver{Prefix: "mymod/v"},
+			wantTag:     "mymod/v3.2.0-beta.1",
+			wantVersion: "3.2.0-beta.1",
+		},
+		{
+			name:        "restrict major",
+			resolver:    TagResolver{Prefix: "mymod/v", RestrictMajor: true, MajorVersion: 2},
+			wantTag:     "mymod/v2.5.0",
+			wantVersion: "2.5.0",
+		},
+		{
+			name:        "restrict major 0",
+			resolver:    TagResolver{Prefix: "v", RestrictMajor: true},
+			wantTag:     "v0.9.0",
+			wantVersion: "0.9.0",
+		},
+		{
+			name:     "no match",
+			resolver: TagResolver{Prefix: "v", RestrictMajor: true, MajorVersion: 5},
+			wantErr:  &NoVersionTagErr{Prefix: "v"},
+		},
+	} {
+		td := td
+		t.Run(td.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+			mockLister := mocks.NewMockTagLister(ctrl)
+			mockLister.EXPECT().ListTags(gomock.Any()).Return(tags, nil)
+			td.resolver.TagLister = mockLister
+			gotTag, gotVersion, err := td.resolver.LatestTag(context.Background())
+			if td.wantErr != nil {
+				require.Equal(t, td.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, td.wantTag, gotTag)
+			require.Equal(t, td.wantVersion, gotVersion)
+		})
+	}
+
+	t.Run("lister error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		mockLister := mocks.NewMockTagLister(ctrl)
+		mockLister.EXPECT().ListTags(gomock.Any()).Return(nil, assert.AnError)
+		resolver := &TagResolver{TagLister: mockLister}
+		_, _, err := resolver.LatestTag(context.Background())
+		require.Equal(t, &TagListerErr{err: assert.AnError}, err)
+	})
+
+	t.Run("nil lister", func(t *testing.T) {
+		require.Panics(t, func() {
+			_, _, err := new(TagResolver).LatestTag(context.Background())
+			require.NoError(t, err)
+		})
+	})
+}
+
+func TestConfig_NextTag(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		ctx := context.Background()
+		mockLister := mocks.NewMockTagLister(ctrl)
+		mockLister.EXPECT().ListTags(ctx).Return([]string{"mymod/v1.2.3", "mymod/v1.1.0", "v3.0.0"}, nil)
+		mockDiscoverer := mocks.NewMockPRDiscoverer(ctrl)
+		mockDiscoverer.EXPECT().DiscoverPRs(ctx, "mymod/v1.2.3", "main").Return([]int{1}, nil)
+		mockFetcher := mocks.NewMockPRLabelFetcher(ctrl)
+		mockFetcher.EXPECT().FetchPRLabels(1).Return([]string{"minor change"}, nil)
+		cfg := &Config{
+			PRLabelFetcher: mockFetcher,
+			PRDiscoverer:   mockDiscoverer,
+		}
+		got, err := cfg.NextTag(ctx, &TagResolver{TagLister: mockLister, Prefix: "mymod/v"}, "main")
+		require.NoError(t, err)
+		require.Equal(t, "mymod/v1.3.0", got)
+	})
+
+	t.Run("no tags", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+		ctx := context.Background()
+		mockLister := mocks.NewMockTagLister(ctrl)
+		mockLister.EXPECT().ListTags(ctx).Return([]string{}, nil)
+		cfg := new(Config)
+		_, err := cfg.NextTag(ctx, &TagResolver{TagLister: mockLister, Prefix: "v"}, "main")
+		require.Equal(t, &NoVersionTagErr{Prefix: "v"}, err)
+	})
+}
+
+func TestNoVersionTagErr(t *testing.T) {
+	err := &NoVersionTagErr{Prefix: "v"}
+	require.Equal(t, `no version tags found with prefix "v"`, err.Error())
+}
+
+func TestTagListerErr(t *testing.T) {
+	err := &TagListerErr{
+		err: assert.AnError,
+	}
+	require.Equal(t, "error from TagLister", err.Error())
+	require.EqualError(t, err.Unwrap(), assert.AnError.Error())
+}